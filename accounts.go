@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+)
+
+// ResolveAccountNames lists every account in the organization and
+// returns a map of account id to account name. This only works when
+// running from the management account or a delegated administrator.
+func ResolveAccountNames(ctx context.Context, cfg aws.Config) (map[string]string, error) {
+	names := map[string]string{}
+
+	p := organizations.NewListAccountsPaginator(organizations.NewFromConfig(cfg), &organizations.ListAccountsInput{})
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range out.Accounts {
+			names[aws.ToString(a.Id)] = aws.ToString(a.Name)
+		}
+	}
+	return names, nil
+}
+
+// ApplyAccountName sets AccountName from names, falling back to the
+// raw account id when the account isn't known
+func ApplyAccountName(r *SingleResource, names map[string]string) {
+	if r.Account == nil {
+		return
+	}
+	name, ok := names[*r.Account]
+	if !ok {
+		name = *r.Account
+	}
+	r.AccountName = &name
+}
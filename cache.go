@@ -0,0 +1,124 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CachePath returns where the results of a scan with the given key are
+// cached, under the user's cache directory (e.g. ~/.cache/awslist). The
+// key should cover everything that changes what a scan returns.
+func CachePath(key string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, "awslist", fmt.Sprintf("%x.json", sum[:8])), nil
+}
+
+// LoadCache reads the resources cached at path. A missing cache isn't an
+// error, it just returns no resources.
+func LoadCache(path string) ([]*SingleResource, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var resources []*SingleResource
+	if err := json.Unmarshal(b, &resources); err != nil {
+		return nil, fmt.Errorf("reading cache %s: %w", path, err)
+	}
+	return resources, nil
+}
+
+// SaveCache writes the resources to path, replacing the previous cache
+func SaveCache(path string, resources []*SingleResource) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(resources)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so an interrupted save doesn't
+	// leave a truncated cache behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// DiffResources compares two scans by ARN, returning the resources only
+// found in the new one and those only found in the old one
+func DiffResources(old, new []*SingleResource) (added, removed []*SingleResource) {
+	oldARNs := map[string]bool{}
+	for _, r := range old {
+		oldARNs[DerefNilPointerStrings(r.ARN)] = true
+	}
+	newARNs := map[string]bool{}
+	for _, r := range new {
+		arn := DerefNilPointerStrings(r.ARN)
+		newARNs[arn] = true
+		if !oldARNs[arn] {
+			added = append(added, r)
+		}
+	}
+	for _, r := range old {
+		if !newARNs[DerefNilPointerStrings(r.ARN)] {
+			removed = append(removed, r)
+		}
+	}
+	return added, removed
+}
+
+// diffDocument is the top level object written for --since-last-scan
+// --output json
+type diffDocument struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	Added         []*SingleResource `json:"added"`
+	Removed       []*SingleResource `json:"removed"`
+}
+
+// RenderDiff writes the added and removed resources, as a JSON document
+// for json output and otherwise as "+ " and "- " prefixed lines
+func RenderDiff(w io.Writer, output string, added, removed []*SingleResource, columns []string, pretty bool) error {
+	if output == "json" {
+		if added == nil {
+			added = []*SingleResource{}
+		}
+		if removed == nil {
+			removed = []*SingleResource{}
+		}
+		return jsonEncoder(w, pretty).Encode(diffDocument{SchemaVersion: OutputSchemaVersion, Added: added, Removed: removed})
+	}
+
+	var lines []string
+	for _, r := range added {
+		lines = append(lines, "+ "+strings.Join(r.row(columns), "/"))
+	}
+	for _, r := range removed {
+		lines = append(lines, "- "+strings.Join(r.row(columns), "/"))
+	}
+	// Sort by the line itself rather than the marker so changes to the
+	// same kind of resource end up next to each other
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i][2:] < lines[j][2:]
+	})
+	for _, l := range lines {
+		if _, err := fmt.Fprintln(w, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
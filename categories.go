@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// otherCategory is where --by-category counts services that aren't in the
+// category map
+const otherCategory = "Other"
+
+// serviceCategories maps ARN service codes to the broad category
+// --by-category counts them under. --category-map adds to or overrides it.
+var serviceCategories = map[string]string{
+	"ec2":                     "Compute",
+	"lambda":                  "Compute",
+	"ecs":                     "Compute",
+	"eks":                     "Compute",
+	"batch":                   "Compute",
+	"elasticbeanstalk":        "Compute",
+	"autoscaling":             "Compute",
+	"application-autoscaling": "Compute",
+	"lightsail":               "Compute",
+	"apprunner":               "Compute",
+	"ecr":                     "Compute",
+	"s3":                      "Storage",
+	"elasticfilesystem":       "Storage",
+	"fsx":                     "Storage",
+	"backup":                  "Storage",
+	"glacier":                 "Storage",
+	"storagegateway":          "Storage",
+	"rds":                     "Database",
+	"dynamodb":                "Database",
+	"elasticache":             "Database",
+	"memorydb":                "Database",
+	"redshift":                "Database",
+	"docdb-elastic":           "Database",
+	"neptune-graph":           "Database",
+	"cassandra":               "Database",
+	"timestream":              "Database",
+	"qldb":                    "Database",
+	"elasticloadbalancing":    "Networking",
+	"cloudfront":              "Networking",
+	"route53":                 "Networking",
+	"route53resolver":         "Networking",
+	"globalaccelerator":       "Networking",
+	"directconnect":           "Networking",
+	"apigateway":              "Networking",
+	"execute-api":             "Networking",
+	"servicediscovery":        "Networking",
+	"network-firewall":        "Networking",
+	"iam":                     "Security",
+	"kms":                     "Security",
+	"acm":                     "Security",
+	"acm-pca":                 "Security",
+	"secretsmanager":          "Security",
+	"wafv2":                   "Security",
+	"shield":                  "Security",
+	"guardduty":               "Security",
+	"securityhub":             "Security",
+	"macie2":                  "Security",
+	"inspector2":              "Security",
+	"detective":               "Security",
+	"access-analyzer":         "Security",
+	"cognito-idp":             "Security",
+	"cognito-identity":        "Security",
+	"verifiedpermissions":     "Security",
+	"identitystore":           "Security",
+	"signer":                  "Security",
+	"sns":                     "Integration",
+	"sqs":                     "Integration",
+	"events":                  "Integration",
+	"scheduler":               "Integration",
+	"states":                  "Integration",
+	"mq":                      "Integration",
+	"kafka":                   "Analytics",
+	"kafkaconnect":            "Analytics",
+	"kinesis":                 "Analytics",
+	"firehose":                "Analytics",
+	"kinesisanalytics":        "Analytics",
+	"glue":                    "Analytics",
+	"athena":                  "Analytics",
+	"elasticmapreduce":        "Analytics",
+	"emr-serverless":          "Analytics",
+	"es":                      "Analytics",
+	"lakeformation":           "Analytics",
+	"databrew":                "Analytics",
+	"sagemaker":               "Machine Learning",
+	"bedrock":                 "Machine Learning",
+	"forecast":                "Machine Learning",
+	"personalize":             "Machine Learning",
+	"comprehend":              "Machine Learning",
+	"rekognition":             "Machine Learning",
+	"logs":                    "Management",
+	"monitoring":              "Management",
+	"cloudtrail":              "Management",
+	"cloudformation":          "Management",
+	"config":                  "Management",
+	"ssm":                     "Management",
+	"organizations":           "Management",
+	"servicecatalog":          "Management",
+	"codebuild":               "Developer Tools",
+	"codecommit":              "Developer Tools",
+	"codedeploy":              "Developer Tools",
+	"codepipeline":            "Developer Tools",
+	"codestar-connections":    "Developer Tools",
+}
+
+// CategoryMap maps ARN service codes to categories, the defaults with the
+// --category-map overrides on top
+type CategoryMap map[string]string
+
+// ParseCategoryMap parses --category-map, e.g. ecs=Containers,sagemaker=AI, on
+// top of the default categories
+func ParseCategoryMap(list string) (CategoryMap, error) {
+	m := CategoryMap{}
+	for code, category := range serviceCategories {
+		m[code] = category
+	}
+	for _, pair := range splitList(list) {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, fmt.Errorf("--category-map entries look like service=Category, got %q", pair)
+		}
+		m[kv[0]] = kv[1]
+	}
+	return m, nil
+}
+
+// Category returns the category of the resource, going by the ARN service
+// code so --friendly-names doesn't change it
+func (m CategoryMap) Category(r *SingleResource) string {
+	code := DerefNilPointerStrings(r.Service)
+	if r.ServiceCode != nil {
+		code = *r.ServiceCode
+	}
+	if category, ok := m[code]; ok {
+		return category
+	}
+	return otherCategory
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Checkpoint records how far a scan got for --resume, the pagination token
+// of the next page of every region started and the regions already done.
+// It's saved after every page, so an interrupted scan can carry on from
+// the page it was on. Args holds what the scan was started with, so it's
+// only resumed with the same regions and filters.
+type Checkpoint struct {
+	path string
+
+	Args   map[string]string `json:"args"`
+	Tokens map[string]string `json:"tokens"`
+	Done   map[string]bool   `json:"done"`
+	// Global are the ARNs of the global resources listed so far, which
+	// every region returns but are only listed the first time
+	Global map[string]bool `json:"global,omitempty"`
+}
+
+// LoadCheckpoint reads the checkpoint at path. A missing file is a fresh
+// checkpoint, nothing has been scanned yet.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	c := &Checkpoint{path: path, Tokens: map[string]string{}, Done: map[string]bool{}, Global: map[string]bool{}}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, err
+	}
+	if c.Global == nil {
+		c.Global = map[string]bool{}
+	}
+	return c, nil
+}
+
+// resumeFlags are the flags deciding which resources a scan lists and how
+// they're written. Resuming with different ones would append a different
+// listing to the output of the interrupted scan.
+var resumeFlags = []string{
+	"resource-type", "service", "tag", "has-tag", "missing-tag", "tags-filter-mode",
+	"filter", "only-global", "only-regional", "approved-regions", "include-commitments",
+	"output", "output-file", "columns", "select", "show-partition", "field-map",
+	"json-flatten", "friendly-names", "resolve-account-names", "enrich", "estimate-cost",
+}
+
+// ScanArgs returns the regions and resumeFlags of this run, as recorded in
+// the checkpoint
+func ScanArgs(regions []string) map[string]string {
+	args := map[string]string{"regions": strings.Join(regions, ",")}
+	for _, name := range resumeFlags {
+		if f := flag.Lookup(name); f != nil {
+			args[name] = f.Value.String()
+		}
+	}
+	return args
+}
+
+// Check makes sure the scan is resumed with the args it was started with,
+// naming the first one that differs. A fresh checkpoint takes the args to
+// save with every page.
+func (c *Checkpoint) Check(args map[string]string) error {
+	if !c.Resuming() {
+		c.Args = args
+		return nil
+	}
+	var names []string
+	for name := range args {
+		names = append(names, name)
+	}
+	for name := range c.Args {
+		if _, ok := args[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if c.Args[name] != args[name] {
+			return fmt.Errorf("%s was %q when the scan started, not %q", name, c.Args[name], args[name])
+		}
+	}
+	return nil
+}
+
+// Resuming reports whether the checkpoint carries on from an earlier scan
+func (c *Checkpoint) Resuming() bool {
+	return len(c.Tokens) > 0 || len(c.Done) > 0
+}
+
+// Next returns the pagination token to start the region from, and whether
+// the region was already scanned completely
+func (c *Checkpoint) Next(region string) (string, bool) {
+	return c.Tokens[region], c.Done[region]
+}
+
+// Save records the token of the region's next page, an empty token marking
+// the region as done
+func (c *Checkpoint) Save(region, token string) error {
+	if token == "" {
+		delete(c.Tokens, region)
+		c.Done[region] = true
+	} else {
+		c.Tokens[region] = token
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	// Same as the cache, a scan interrupted mid write shouldn't leave a
+	// truncated checkpoint behind
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// Remove deletes the checkpoint once the scan is complete
+func (c *Checkpoint) Remove() error {
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
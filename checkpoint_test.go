@@ -0,0 +1,50 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckpointOnlyResumesTheSameScan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	args := map[string]string{"regions": "us-east-1,eu-west-1", "resource-type": "ec2:instance", "output": "jsonl"}
+
+	c, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Check(args); err != nil {
+		t.Fatalf("fresh checkpoint: %v", err)
+	}
+	c.Global["arn:aws:iam::123456789012:role/deploy"] = true
+	if err := c.Save("us-east-1", "page-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token, done := c.Next("us-east-1"); token != "page-2" || done {
+		t.Errorf("got token %q, done %v, want page-2 and not done", token, done)
+	}
+	if err := c.Check(args); err != nil {
+		t.Errorf("same args: %v", err)
+	}
+	// The global resources listed so far are saved too, so the resumed scan
+	// doesn't list them again
+	if !c.Global["arn:aws:iam::123456789012:role/deploy"] {
+		t.Errorf("the global resources listed so far weren't saved: %v", c.Global)
+	}
+
+	for name, value := range map[string]string{"resource-type": "s3", "regions": "us-east-1", "service": "ec2"} {
+		other := map[string]string{}
+		for k, v := range args {
+			other[k] = v
+		}
+		other[name] = value
+		if err := c.Check(other); err == nil {
+			t.Errorf("resumed with a different %s", name)
+		}
+	}
+}
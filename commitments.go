@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/savingsplans"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// commitmentsNote explains why Reserved Instances and Savings Plans don't
+// show up in a normal scan
+const commitmentsNote = `Reserved Instances and Savings Plans are billing commitments, not resources
+that can be tagged, so the tagging API never returns them. --include-commitments
+lists EC2 and RDS Reserved Instances and Savings Plans with their own describe
+calls.`
+
+// CallerAccount returns the id of the account the credentials belong to
+func CallerAccount(ctx context.Context, cfg aws.Config) (string, error) {
+	id, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(id.Account), nil
+}
+
+// regionPartition returns the partition a region is in
+func regionPartition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	}
+	return "aws"
+}
+
+// ListCommitments lists the EC2 and RDS Reserved Instances of the region
+// for --include-commitments, as resources built from their ARNs like the
+// tagged ones. Details says how many of what they reserve, their state and
+// when they end.
+func ListCommitments(ctx context.Context, cfg aws.Config, region, account string) ([]*SingleResource, error) {
+	var commitments []*SingleResource
+	add := func(arn, service, details string, tags Tags) {
+		r := ConvertArnToSingleResource(&arn, &service, &region)
+		r.Details = &details
+		r.Tags = tags
+		commitments = append(commitments, r)
+	}
+
+	ec2Client := ec2.NewFromConfig(cfg, func(o *ec2.Options) {
+		o.Region = region
+	})
+	reserved, err := ec2Client.DescribeReservedInstances(ctx, &ec2.DescribeReservedInstancesInput{})
+	if err != nil {
+		return commitments, err
+	}
+	for _, ri := range reserved.ReservedInstances {
+		// EC2 doesn't hand out Reserved Instance ARNs, this is the form
+		// IAM policies use for them
+		arn := fmt.Sprintf("arn:%s:ec2:%s:%s:reserved-instances/%s", regionPartition(region), region, account, aws.ToString(ri.ReservedInstancesId))
+		var tags Tags
+		for _, t := range ri.Tags {
+			if tags == nil {
+				tags = Tags{}
+			}
+			tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+		}
+		add(arn, "ec2", commitmentDetails(int(aws.ToInt32(ri.InstanceCount)), string(ri.InstanceType), string(ri.State), ri.End), tags)
+	}
+
+	rdsClient := rds.NewFromConfig(cfg, func(o *rds.Options) {
+		o.Region = region
+	})
+	p := rds.NewDescribeReservedDBInstancesPaginator(rdsClient, &rds.DescribeReservedDBInstancesInput{})
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return commitments, err
+		}
+		for _, ri := range out.ReservedDBInstances {
+			var end *time.Time
+			if ri.StartTime != nil {
+				e := ri.StartTime.Add(time.Duration(ri.Duration) * time.Second)
+				end = &e
+			}
+			add(aws.ToString(ri.ReservedDBInstanceArn), "rds", commitmentDetails(int(ri.DBInstanceCount), aws.ToString(ri.DBInstanceClass), aws.ToString(ri.State), end), nil)
+		}
+	}
+	return commitments, nil
+}
+
+// commitmentDetails describes a Reserved Instance, e.g. "3x m5.large,
+// active until 2026-01-31"
+func commitmentDetails(count int, class, state string, end *time.Time) string {
+	details := fmt.Sprintf("%dx %s, %s", count, class, state)
+	if end != nil {
+		details += " until " + end.Format("2006-01-02")
+	}
+	return details
+}
+
+// ListSavingsPlans lists the Savings Plans of the account for
+// --include-commitments. They aren't tied to a region, so they're listed
+// once as global resources.
+func ListSavingsPlans(ctx context.Context, cfg aws.Config) ([]*SingleResource, error) {
+	client := savingsplans.NewFromConfig(cfg, func(o *savingsplans.Options) {
+		o.Region = "us-east-1"
+	})
+	var plans []*SingleResource
+	var nextToken *string
+	for {
+		out, err := client.DescribeSavingsPlans(ctx, &savingsplans.DescribeSavingsPlansInput{NextToken: nextToken})
+		if err != nil {
+			return plans, err
+		}
+		for _, sp := range out.SavingsPlans {
+			arn, service, region := aws.ToString(sp.SavingsPlanArn), "savingsplans", globalRegion
+			r := ConvertArnToSingleResource(&arn, &service, &region)
+			// End is a timestamp like 2027-01-31T00:00:00.000Z, the day is
+			// plenty
+			end := aws.ToString(sp.End)
+			if len(end) > 10 {
+				end = end[:10]
+			}
+			details := fmt.Sprintf("%s, %s %s/hour, %s until %s", sp.SavingsPlanType, aws.ToString(sp.Commitment), sp.Currency, sp.State, end)
+			r.Details = &details
+			if len(sp.Tags) > 0 {
+				r.Tags = Tags(sp.Tags)
+			}
+			plans = append(plans, r)
+		}
+		if aws.ToString(out.NextToken) == "" {
+			return plans, nil
+		}
+		nextToken = out.NextToken
+	}
+}
@@ -0,0 +1,1417 @@
+package main
+
+import "strings"
+
+// GetServiceFromArn returns the service segment of the ARN, whatever
+// partition (aws, aws-cn, aws-us-gov) it's in
+func ServiceNameFromARN(arn *string) *string {
+	sliced := strings.SplitN(*arn, ":", 4)
+	if len(sliced) < 3 {
+		return &sliced[0]
+	}
+	return &sliced[2]
+}
+
+// Short ARN removes the unnecessary info from the ARN we already
+// know at this point like region, account id and the service name.
+func ShortArn(arn *string) string {
+	slicedArn := strings.Split(*arn, ":")
+	if len(slicedArn) < 6 {
+		return *arn
+	}
+	shortArn := slicedArn[5:]
+	return strings.Join(shortArn, "/")
+}
+
+// awsEC2 type is created for ARNs belonging to the EC2 service
+type awsEC2 string
+
+// awsSSM type is created for ARNs belonging to the Systems Manager service
+type awsSSM string
+
+// awsECS type is created for ARNs belonging to the ECS service
+type awsECS string
+
+// awsLogs type is created for ARNs belonging to the CloudWatch Logs service
+type awsLogs string
+
+// awsRDS type is created for ARNs belonging to the RDS service, which
+// also covers Neptune and DocumentDB as they share the same ARN format
+// (cluster:my-docdb, db:my-neptune). Automated snapshot ids have a colon
+// of their own (snapshot:rds:my-db-2024-01-01-00-00).
+type awsRDS struct{ colonDelimitedConverter }
+
+// awsAthena type is created for ARNs belonging to the Athena service
+type awsAthena string
+
+// awsQuickSight type is created for ARNs belonging to the QuickSight service
+type awsQuickSight string
+
+// awsMediaLive type is created for ARNs belonging to the MediaLive service
+type awsMediaLive string
+
+// awsMediaConvert type is created for ARNs belonging to the MediaConvert service
+type awsMediaConvert string
+
+// awsTransfer type is created for ARNs belonging to the Transfer Family service
+type awsTransfer string
+
+// awsDataSync type is created for ARNs belonging to the DataSync service
+type awsDataSync string
+
+// awsGuardDuty type is created for ARNs belonging to the GuardDuty service
+type awsGuardDuty string
+
+// awsSecurityHub type is created for ARNs belonging to the Security Hub service
+type awsSecurityHub string
+
+// awsCodeBuild type is created for ARNs belonging to the CodeBuild service
+type awsCodeBuild string
+
+// awsCodePipeline type is created for ARNs belonging to the CodePipeline service
+type awsCodePipeline string
+
+// awsCodeCommit type is created for ARNs belonging to the CodeCommit service
+type awsCodeCommit string
+
+// awsSES type is created for ARNs belonging to the SES service
+type awsSES string
+
+// awsPinpoint type is created for ARNs belonging to the Pinpoint
+// (mobiletargeting) service
+type awsPinpoint string
+
+// awsGlobalAccelerator type is created for ARNs belonging to the
+// Global Accelerator service
+type awsGlobalAccelerator string
+
+// awsDirectConnect type is created for ARNs belonging to the Direct Connect service
+type awsDirectConnect string
+
+// awsMacie type is created for ARNs belonging to the Macie (macie2) service
+type awsMacie string
+
+// awsInspector type is created for ARNs belonging to the Inspector
+// (inspector2) service
+type awsInspector string
+
+// awsAutoScaling type is created for ARNs belonging to the Auto Scaling service
+type awsAutoScaling string
+
+// awsACM type is created for ARNs belonging to the Certificate Manager service
+type awsACM string
+
+// awsACMPCA type is created for ARNs belonging to the ACM Private CA service
+type awsACMPCA string
+
+// awsServiceCatalog type is created for ARNs belonging to the Service
+// Catalog service, which uses both the catalog and servicecatalog codes
+type awsServiceCatalog string
+
+// awsResourceGroups type is created for ARNs belonging to the Resource Groups service
+type awsResourceGroups string
+
+// awsRedshift type is created for ARNs belonging to the Redshift service
+// (cluster:my-cluster). Snapshots also name their cluster
+// (snapshot:my-cluster/my-snapshot) which goes into Details.
+type awsRedshift struct{ colonDelimitedConverter }
+
+// awsTimestream type is created for ARNs belonging to the Timestream service
+type awsTimestream string
+
+// awsMSK type is created for ARNs belonging to the MSK (kafka) service
+type awsMSK string
+
+// awsEMR type is created for ARNs belonging to the EMR
+// (elasticmapreduce) service
+type awsEMR string
+
+// awsConfig type is created for ARNs belonging to the AWS Config service
+type awsConfig string
+
+// configResourceTypes shortens the AWS Config resource types that repeat
+// the config- prefix of the service
+var configResourceTypes = map[string]string{
+	"config-aggregator": "aggregator",
+}
+
+// awsAmplify type is created for ARNs belonging to the Amplify service
+type awsAmplify string
+
+// awsAppRunner type is created for ARNs belonging to the App Runner service
+type awsAppRunner string
+
+// awsLambda type is created for ARNs belonging to the Lambda service
+type awsLambda string
+
+// awsEventBridge type is created for ARNs belonging to the EventBridge
+// (events) service
+type awsEventBridge string
+
+// awsSageMaker type is created for ARNs belonging to the SageMaker service
+type awsSageMaker string
+
+// awsIoT type is created for ARNs belonging to the IoT Core service
+type awsIoT string
+
+// iotResourceTypes names the IoT resource types whose ARN type is
+// abbreviated after what the API and console call them
+var iotResourceTypes = map[string]string{
+	"rule": "topicrule",
+	"cert": "certificate",
+}
+
+// awsGameLift type is created for ARNs belonging to the GameLift service
+type awsGameLift string
+
+// awsAIService type is created for ARNs belonging to the Comprehend,
+// Translate and Rekognition services, which all share the same shapes
+type awsAIService string
+
+// awsMQ type is created for ARNs belonging to the Amazon MQ service
+type awsMQ string
+
+// awsMemoryDB type is created for ARNs belonging to the MemoryDB service
+type awsMemoryDB string
+
+// awsWorkSpaces type is created for ARNs belonging to the WorkSpaces service
+type awsWorkSpaces string
+
+// awsAppStream type is created for ARNs belonging to the AppStream 2.0 service
+type awsAppStream string
+
+// awsCodeArtifact type is created for ARNs belonging to the CodeArtifact service
+type awsCodeArtifact string
+
+// awsCodeDeploy type is created for ARNs belonging to the CodeDeploy
+// service. Applications are application:name, deployment groups are
+// deploymentgroup:application/name with the application in Details.
+type awsCodeDeploy struct{ colonDelimitedConverter }
+
+// awsVPCLattice type is created for ARNs belonging to the VPC Lattice service
+type awsVPCLattice string
+
+// awsShield type is created for ARNs belonging to the Shield service
+type awsShield string
+
+// awsNetworkFirewall type is created for ARNs belonging to the Network
+// Firewall service
+type awsNetworkFirewall string
+
+// awsLightsail type is created for ARNs belonging to the Lightsail service
+type awsLightsail string
+
+// lightsailResourceTypes are the Products of the Lightsail resource types
+// that read better than their lowercased ARN type
+var lightsailResourceTypes = map[string]string{
+	"RelationalDatabase":         "database",
+	"RelationalDatabaseSnapshot": "databasesnapshot",
+	"LoadBalancerTlsCertificate": "loadbalancercertificate",
+}
+
+// awsOutposts type is created for ARNs belonging to the Outposts service
+type awsOutposts string
+
+// awsDMS type is created for ARNs belonging to the Database Migration
+// Service
+type awsDMS string
+
+// dmsResourceTypes spells out the abbreviated resource types DMS uses in
+// its ARNs
+var dmsResourceTypes = map[string]string{
+	"rep":    "replication-instance",
+	"subgrp": "subnet-group",
+	"cert":   "certificate",
+	"es":     "event-subscription",
+}
+
+// awsConnect type is created for ARNs belonging to the Amazon Connect service
+type awsConnect string
+
+// awsElastiCache type is created for ARNs belonging to the ElastiCache
+// service (cluster:name, replicationgroup:name, snapshot:name, ...)
+type awsElastiCache struct{ colonDelimitedConverter }
+
+// awsSNS type is created for ARNs belonging to the SNS service. Topic ARNs
+// are just the topic name.
+type awsSNS struct{ colonDelimitedConverter }
+
+// awsSQS type is created for ARNs belonging to the SQS service. Queue ARNs
+// are just the queue name.
+type awsSQS struct{ colonDelimitedConverter }
+
+// awsStepFunctions type is created for ARNs belonging to the Step Functions
+// (states) service (stateMachine:name, activity:name)
+type awsStepFunctions struct{ colonDelimitedConverter }
+
+var (
+	rdsConverter           = awsRDS{colonDelimitedConverter{TypeSegment: 0, IDSegment: 1, JoinID: true}}
+	redshiftConverter      = awsRedshift{colonDelimitedConverter{TypeSegment: 0, IDSegment: 1, Scoped: true}}
+	codeDeployConverter    = awsCodeDeploy{colonDelimitedConverter{TypeSegment: 0, IDSegment: 1, Scoped: true}}
+	elastiCacheConverter   = awsElastiCache{colonDelimitedConverter{TypeSegment: 0, IDSegment: 1}}
+	snsConverter           = awsSNS{colonDelimitedConverter{Product: "topic", IDSegment: 0}}
+	sqsConverter           = awsSQS{colonDelimitedConverter{Product: "queue", IDSegment: 0}}
+	stepFunctionsConverter = awsStepFunctions{colonDelimitedConverter{TypeSegment: 0, IDSegment: 1}}
+)
+
+// awsStorageGateway type is created for ARNs belonging to the Storage
+// Gateway service
+type awsStorageGateway string
+
+// awsSnowball type is created for ARNs belonging to the Snow family
+// (snowball) service
+type awsSnowball string
+
+// awsBeanstalk type is created for ARNs belonging to the Elastic Beanstalk
+// service
+type awsBeanstalk string
+
+// awsChime type is created for ARNs belonging to the Chime service
+type awsChime string
+
+// awsWorkMail type is created for ARNs belonging to the WorkMail service
+type awsWorkMail string
+
+// awsApplicationAutoScaling type is created for ARNs belonging to the
+// Application Auto Scaling service
+type awsApplicationAutoScaling string
+
+// awsCloudTrail type is created for ARNs belonging to the CloudTrail service
+type awsCloudTrail string
+
+// awsGreengrass type is created for ARNs belonging to the Greengrass service
+type awsGreengrass string
+
+// awsPanorama type is created for ARNs belonging to the Panorama service
+type awsPanorama string
+
+// awsDatasetService type is created for ARNs belonging to the Forecast and
+// Personalize services, which share the same shapes
+type awsDatasetService string
+
+// awsSigner type is created for ARNs belonging to the Signer service
+type awsSigner string
+
+// awsBedrock type is created for ARNs belonging to the Bedrock service
+type awsBedrock string
+
+// awsQBusiness type is created for ARNs belonging to the Amazon Q Business service
+type awsQBusiness string
+
+// awsVerifiedPermissions type is created for ARNs belonging to the Verified
+// Permissions service
+type awsVerifiedPermissions string
+
+// awsIdentityStore type is created for ARNs belonging to the Identity Store service
+type awsIdentityStore string
+
+// awsLakeFormation type is created for ARNs belonging to the Lake Formation
+// service, kept apart from the Glue catalog resources it governs
+type awsLakeFormation string
+
+// awsScheduler type is created for ARNs belonging to the EventBridge Scheduler service
+type awsScheduler string
+
+// awsSMSVoice type is created for ARNs belonging to the End User Messaging
+// SMS and voice service
+type awsSMSVoice string
+
+// awsRoute53Resolver type is created for ARNs belonging to the Route 53
+// Resolver service, which unlike Route 53 itself is regional
+type awsRoute53Resolver string
+
+// awsDetective type is created for ARNs belonging to the Detective service
+type awsDetective string
+
+// awsAccessAnalyzer type is created for ARNs belonging to the IAM Access
+// Analyzer service
+type awsAccessAnalyzer string
+
+// awsDataBrew type is created for ARNs belonging to the Glue DataBrew service
+type awsDataBrew string
+
+// awsEMRServerless type is created for ARNs belonging to the EMR Serverless
+// service, whose resources are path style unlike EMR on EC2
+type awsEMRServerless string
+
+// awsRAM type is created for ARNs belonging to the Resource Access Manager
+// service
+type awsRAM string
+
+// awsGlue type is created for ARNs belonging to the Glue service
+type awsGlue string
+
+// awsKafkaConnect type is created for ARNs belonging to the MSK Connect
+// service
+type awsKafkaConnect string
+
+// awsQLDB type is created for ARNs belonging to the QLDB service
+type awsQLDB string
+
+// awsWAFv2 type is created for ARNs belonging to the WAFv2 service
+type awsWAFv2 string
+
+// awsGeneric is a is a generic AWS for services ARNs that don't have
+// a dedicated type within our application.
+type awsGeneric string
+
+// Generic Resource Handler
+func (aws *awsGeneric) ConverToResource(shortArn, svc, rgn *string) *SingleResource {
+	return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, ID: shortArn}
+}
+
+// ConvertToRow converts EC2 shortened ARNs to to a SingleResource type.
+// EC2 has lots of resource types (instance, spot-instances-request,
+// network-interface, security-group, vpc, subnet, volume, snapshot, ...)
+// which all follow the type/id shape. That includes the VPC IPAM ones
+// (ipam, ipam-pool, ipam-scope, ipam-resource-discovery), whose ARNs have
+// no region.
+func (aws *awsEC2) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Systems Manager shortened ARNs
+// (managed-instance/mi-123, parameter/path/name, document/name) to a
+// SingleResource type
+func (aws *awsSSM) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts ECS shortened ARNs to a SingleResource type.
+// Clusters are cluster/name, but services, tasks and container instances
+// live under their cluster (service/cluster/name, task/cluster/id) whose
+// name goes into Details, as do task sets under their cluster and service
+// (task-set/cluster/service/id). Task definitions are family:revision,
+// the revision going into Details like a Lambda alias. ARNs in the old
+// format without the cluster are plain type/id.
+func (aws *awsECS) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	switch {
+	case s[0] == "task-definition" && len(s) == 3:
+		return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &s[1], Details: &s[2]}
+	case len(s) == 3:
+		return scopedResource(shortArn, svc, rgn)
+	case s[0] == "task-set" && len(s) > 3:
+		// The task set id itself has a slash, ecs-svc/1234567890123456789
+		parents, id := s[1]+"/"+s[2], strings.Join(s[3:], "/")
+		return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &id, Details: &parents}
+	}
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts CloudWatch Logs shortened ARNs to a SingleResource
+// type. Log group names can contain slashes themselves and the ARN carries a
+// trailing ":*" wildcard, so we keep everything after the resource type and
+// drop the wildcard.
+func (aws *awsLogs) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.SplitN(*shortArn, "/", 2)
+	if len(s) < 2 {
+		return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, ID: shortArn}
+	}
+	id := strings.TrimSuffix(s[1], "/*")
+	return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &id}
+}
+
+// ConvertToResource converts Athena shortened ARNs (workgroup/primary,
+// datacatalog/name) to a SingleResource type
+func (aws *awsAthena) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts QuickSight shortened ARNs (dashboard/id,
+// dataset/id, analysis/id) to a SingleResource type
+func (aws *awsQuickSight) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts MediaLive shortened ARNs (channel:123,
+// input:456, multiplex:789) to a SingleResource type
+func (aws *awsMediaLive) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts MediaConvert shortened ARNs (queues/Default,
+// presets/name, jobTemplates/name) to a SingleResource type
+func (aws *awsMediaConvert) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Transfer Family shortened ARNs to a
+// SingleResource type. Users are scoped to their server
+// (user/s-123/name) so the server id goes into Details.
+func (aws *awsTransfer) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	if s[0] == "user" && len(s) == 3 {
+		return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &s[2], Details: &s[1]}
+	}
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts DataSync shortened ARNs (task/task-123,
+// location/loc-123, agent/agent-123) to a SingleResource type
+func (aws *awsDataSync) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts GuardDuty shortened ARNs to a SingleResource
+// type. Filters, IP sets and threat intel sets live under their detector
+// (detector/id/filter/name), in which case the detector id goes into Details.
+func (aws *awsGuardDuty) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return childResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Security Hub shortened ARNs (hub/default,
+// standards/name) to a SingleResource type
+func (aws *awsSecurityHub) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts CodeBuild shortened ARNs (project/name,
+// report-group/name) to a SingleResource type
+func (aws *awsCodeBuild) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts CodePipeline shortened ARNs to a
+// SingleResource type. Pipelines are just the name with no resource
+// type in front, webhooks come as webhook:name.
+func (aws *awsCodePipeline) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return namedResource("pipeline", shortArn, svc, rgn)
+}
+
+// ConvertToResource converts CodeCommit shortened ARNs, which are just
+// the repository name, to a SingleResource type
+func (aws *awsCodeCommit) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return namedResource("repository", shortArn, svc, rgn)
+}
+
+// namedResource handles services whose main resource ARN is just its
+// name with no resource type in front, labelling those with product.
+// Anything else is expected to be in the usual type/id shape.
+func namedResource(product string, shortArn, svc, rgn *string) *SingleResource {
+	if !strings.Contains(*shortArn, "/") {
+		return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &product, ID: shortArn}
+	}
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts SES shortened ARNs (identity/example.com,
+// configuration-set/name) to a SingleResource type
+func (aws *awsSES) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Pinpoint shortened ARNs to a SingleResource
+// type. Campaigns and segments live under their app
+// (apps/id/campaigns/id), in which case the app id goes into Details.
+func (aws *awsPinpoint) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return childResource(shortArn, svc, rgn)
+}
+
+// childResource handles resources scoped to a parent in the
+// parent-type/parent-id/type/id shape, using the child's type and id and
+// putting the parent id into Details. Anything else is treated as type/id.
+func childResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	if len(s) == 4 {
+		return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[2], ID: &s[3], Details: &s[1]}
+	}
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Global Accelerator shortened ARNs to a
+// SingleResource type. Global Accelerator is a global service (its ARNs
+// have an empty region) so the Region is always globalRegion. Listeners
+// and endpoint groups live under their accelerator
+// (accelerator/id/listener/id) whose id goes into Details.
+func (aws *awsGlobalAccelerator) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	global := globalRegion
+	return childResource(shortArn, svc, &global)
+}
+
+// ConvertToResource converts Direct Connect shortened ARNs (dxcon/id,
+// dxvif/id, dxlag/id) to a SingleResource type
+func (aws *awsDirectConnect) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Macie shortened ARNs (classification-job/id,
+// custom-data-identifier/id, findings-filter/id, allow-list/id,
+// member/account) to a SingleResource type
+func (aws *awsMacie) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Inspector shortened ARNs to a SingleResource
+// type. Filters are scoped to their owner account (owner/account/filter/id)
+// which goes into Details.
+func (aws *awsInspector) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return childResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Auto Scaling shortened ARNs to a
+// SingleResource type. These look like
+// autoScalingGroup/uuid/autoScalingGroupName/my-asg, or for policies
+// scalingPolicy/uuid/autoScalingGroupName/my-asg/policyName/my-policy, so
+// the last name is the ID and the uuid goes into Details.
+func (aws *awsAutoScaling) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	if len(s) < 4 {
+		return typeAndIDResource(shortArn, svc, rgn)
+	}
+	return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &s[len(s)-1], Details: &s[1]}
+}
+
+// ConvertToResource converts ACM shortened ARNs (certificate/uuid) to a
+// SingleResource type
+func (aws *awsACM) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts ACM Private CA shortened ARNs to a
+// SingleResource type. Certificates issued by a CA live under it
+// (certificate-authority/id/certificate/id) and get the CA id in Details.
+func (aws *awsACMPCA) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return childResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Service Catalog shortened ARNs to a
+// SingleResource type. Products and portfolios (product/prod-id,
+// portfolio/port-id) use the catalog code, while AppRegistry uses
+// servicecatalog with a path style resource (/applications/id).
+func (aws *awsServiceCatalog) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return pathResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Resource Groups shortened ARNs (group/name)
+// to a SingleResource type
+func (aws *awsResourceGroups) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// pathResource handles the path style resources some services use, which
+// start with a slash (/applications/id), by dropping the leading slash and
+// treating the rest as type/id.
+func pathResource(shortArn, svc, rgn *string) *SingleResource {
+	trimmed := strings.TrimPrefix(*shortArn, "/")
+	res := typeAndIDResource(&trimmed, svc, rgn)
+	res.ARN = shortArn
+	return res
+}
+
+// ConvertToResource converts Timestream shortened ARNs to a SingleResource
+// type. Tables are nested under their database (database/db/table/tbl)
+// and get db.tbl as ID.
+func (aws *awsTimestream) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	if s[0] == "database" && len(s) == 4 {
+		id := s[1] + "." + s[3]
+		return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[2], ID: &id}
+	}
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts MSK shortened ARNs (cluster/name/uuid,
+// configuration/name/uuid) to a SingleResource type
+func (aws *awsMSK) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return nameAndUUIDResource(shortArn, svc, rgn)
+}
+
+// nameAndUUIDResource handles the type/name/uuid shape, where the uuid
+// just clutters the ID and goes into Details instead
+func nameAndUUIDResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	if len(s) != 3 {
+		return typeAndIDResource(shortArn, svc, rgn)
+	}
+	return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &s[1], Details: &s[2]}
+}
+
+// ConvertToResource converts EMR shortened ARNs (cluster/j-XXXX,
+// editor/e-XXXX) to a SingleResource type
+func (aws *awsEMR) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts AWS Config shortened ARNs (config-rule/id,
+// config-aggregator/id, conformance-pack/name/id) to a SingleResource type.
+// Aggregators are listed as aggregator.
+func (aws *awsConfig) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	res := nameAndUUIDResource(shortArn, svc, rgn)
+	if res.Product != nil {
+		if product, ok := configResourceTypes[*res.Product]; ok {
+			res.Product = &product
+		}
+	}
+	return res
+}
+
+// ConvertToResource converts Amplify shortened ARNs to a SingleResource
+// type. Everything lives under an app (apps/id/branches/name), so the app
+// id is used as ID and the branch or domain goes into Details.
+func (aws *awsAmplify) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	if len(s) < 4 || s[0] != "apps" {
+		return typeAndIDResource(shortArn, svc, rgn)
+	}
+	details := strings.Join(s[3:], "/")
+	return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[2], ID: &s[1], Details: &details}
+}
+
+// ConvertToResource converts App Runner shortened ARNs (service/name/id,
+// connection/name/id) to a SingleResource type
+func (aws *awsAppRunner) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return nameAndUUIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Lambda shortened ARNs to a SingleResource
+// type. Functions (function/name, function/name/alias) and layers
+// (layer/name/version) keep the alias or version in Details, event source
+// mappings are just event-source-mapping/uuid.
+func (aws *awsLambda) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return nameAndUUIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts EventBridge shortened ARNs to a SingleResource
+// type. Rules on the default bus are rule/name, rules on other buses are
+// rule/bus/name and get the bus in Details. Buses are event-bus/name and
+// API destinations api-destination/name/uuid.
+func (aws *awsEventBridge) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	if strings.HasPrefix(*shortArn, "rule/") {
+		return scopedResource(shortArn, svc, rgn)
+	}
+	return nameAndUUIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts SageMaker shortened ARNs (notebook-instance/name,
+// endpoint/name, model/name, training-job/name, ...) to a SingleResource type
+func (aws *awsSageMaker) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts IoT shortened ARNs (thing/name, policy/name,
+// rule/name, cert/id, ...) to a SingleResource type. Rules and
+// certificates get the topicrule and certificate Products.
+func (aws *awsIoT) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	res := typeAndIDResource(shortArn, svc, rgn)
+	if res.Product != nil {
+		if product, ok := iotResourceTypes[*res.Product]; ok {
+			res.Product = &product
+		}
+	}
+	return res
+}
+
+// ConvertToResource converts GameLift shortened ARNs (fleet/id, alias/id,
+// build/id, gamesessionqueue/name) to a SingleResource type
+func (aws *awsGameLift) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts AI service shortened ARNs to a SingleResource
+// type. They're type/name, optionally followed by a version or creation
+// timestamp (document-classifier/name/version/v1, project/name/1234567890)
+// which goes into Details.
+func (aws *awsAIService) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.SplitN(*shortArn, "/", 3)
+	if len(s) < 3 {
+		return typeAndIDResource(shortArn, svc, rgn)
+	}
+	return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &s[1], Details: &s[2]}
+}
+
+// ConvertToResource converts Amazon MQ shortened ARNs to a SingleResource
+// type. Brokers are broker:name:id, the id going into Details, and
+// configurations are configuration:id.
+func (aws *awsMQ) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return nameAndUUIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts MemoryDB shortened ARNs (cluster/name,
+// user/name, acl/name, ...) to a SingleResource type
+func (aws *awsMemoryDB) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts WorkSpaces shortened ARNs (workspace/ws-id,
+// directory/d-id, workspacebundle/wsb-id) to a SingleResource type
+func (aws *awsWorkSpaces) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts AppStream shortened ARNs (fleet/name,
+// stack/name, image/name, image-builder/name) to a SingleResource type
+func (aws *awsAppStream) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts CodeArtifact shortened ARNs to a SingleResource
+// type. Domains are domain/name, repositories repository/domain/name with
+// the domain in Details.
+func (aws *awsCodeArtifact) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return scopedResource(shortArn, svc, rgn)
+}
+
+// scopedResource handles the type/parent/name shape of resources that
+// live inside another one, like a repository in a domain. The parent goes
+// into Details.
+func scopedResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	if len(s) != 3 {
+		return typeAndIDResource(shortArn, svc, rgn)
+	}
+	return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &s[2], Details: &s[1]}
+}
+
+// ConvertToResource converts VPC Lattice shortened ARNs (service/id,
+// servicenetwork/id, targetgroup/id, ...) to a SingleResource type
+func (aws *awsVPCLattice) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Shield shortened ARNs (protection/id,
+// protection-group/id) to a SingleResource type. Shield is global, its
+// ARNs have an empty region, so the Region is always globalRegion.
+func (aws *awsShield) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	global := globalRegion
+	return typeAndIDResource(shortArn, svc, &global)
+}
+
+// ConvertToResource converts Network Firewall shortened ARNs (firewall/name,
+// firewall-policy/name, stateful-rulegroup/name, ...) to a SingleResource
+// type. Both kinds of rule group are listed as rulegroup, with stateful or
+// stateless in Details.
+func (aws *awsNetworkFirewall) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	res := typeAndIDResource(shortArn, svc, rgn)
+	if res.Product != nil && strings.HasSuffix(*res.Product, "-rulegroup") {
+		kind := strings.TrimSuffix(*res.Product, "-rulegroup")
+		product := "rulegroup"
+		res.Product, res.Details = &product, &kind
+	}
+	return res
+}
+
+// ConvertToResource converts Lightsail shortened ARNs (Instance/id,
+// RelationalDatabase/id, LoadBalancer/id, ...) to a SingleResource type.
+// Lightsail capitalises its resource types, they're renamed or just
+// lowercased here so the Product column looks like every other service's.
+func (aws *awsLightsail) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	res := typeAndIDResource(shortArn, svc, rgn)
+	if res.Product != nil {
+		product, ok := lightsailResourceTypes[*res.Product]
+		if !ok {
+			product = strings.ToLower(*res.Product)
+		}
+		res.Product = &product
+	}
+	return res
+}
+
+// ConvertToResource converts Outposts shortened ARNs (outpost/op-id,
+// site/os-id, order/oo-id) to a SingleResource type
+func (aws *awsOutposts) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts DMS shortened ARNs (rep:id, endpoint:id,
+// task:id, ...) to a SingleResource type
+func (aws *awsDMS) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	res := typeAndIDResource(shortArn, svc, rgn)
+	if res.Product != nil {
+		if product, ok := dmsResourceTypes[*res.Product]; ok {
+			res.Product = &product
+		}
+	}
+	return res
+}
+
+// ConvertToResource converts Amazon Connect shortened ARNs to a
+// SingleResource type. Almost everything lives under an instance
+// (instance/id/queue/id, instance/id/contact-flow/id, ...), so the leaf's
+// type and id are used and the instance id, along with anything else
+// between the two, goes into Details.
+func (aws *awsConnect) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	if len(s) < 4 || s[0] != "instance" {
+		return typeAndIDResource(shortArn, svc, rgn)
+	}
+	details := strings.Join(s[1:len(s)-2], "/")
+	return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[len(s)-2], ID: &s[len(s)-1], Details: &details}
+}
+
+// colonDelimitedConverter is the base for the many services whose ARNs end
+// in resourcetype:id (or just the id), so they only declare where the type
+// and id are. Segments after the id go into Details. It needs the segments
+// themselves to be free of slashes, which rules out e.g. CloudWatch Logs.
+type colonDelimitedConverter struct {
+	// Product is used as is for ARNs without a resource type segment,
+	// TypeSegment is ignored when it's set
+	Product string
+	// TypeSegment and IDSegment index the segments after the account id
+	TypeSegment, IDSegment int
+	// Scoped is for resources living inside another one
+	// (type:parent/id), the id is then the last segment and everything
+	// between the type and the id goes into Details
+	Scoped bool
+	// JoinID keeps the segments after the id in the ID, for ids which
+	// have colons of their own
+	JoinID bool
+}
+
+// ConvertToResource converts a shortened ARN to a SingleResource type.
+// ShortArn has already turned the colons into slashes.
+func (c colonDelimitedConverter) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	if c.IDSegment >= len(s) || (c.Product == "" && c.TypeSegment >= len(s)) {
+		return typeAndIDResource(shortArn, svc, rgn)
+	}
+
+	product := c.Product
+	if product == "" {
+		product = s[c.TypeSegment]
+	}
+	id, details := s[c.IDSegment], strings.Join(s[c.IDSegment+1:], ":")
+	switch {
+	case c.JoinID:
+		id, details = strings.Join(s[c.IDSegment:], ":"), ""
+	case c.Scoped && len(s) > c.IDSegment+1:
+		id, details = s[len(s)-1], strings.Join(s[c.IDSegment:len(s)-1], "/")
+	}
+	res := &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &product, ID: &id}
+	if details != "" {
+		res.Details = &details
+	}
+	return res
+}
+
+// ConvertToResource converts Storage Gateway shortened ARNs to a
+// SingleResource type. Volumes and tapes live under their gateway
+// (gateway/sgw-id/volume/vol-id) whose id goes into Details.
+func (aws *awsStorageGateway) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return childResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Snowball shortened ARNs (job/JID...,
+// cluster/CID...) to a SingleResource type
+func (aws *awsSnowball) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Elastic Beanstalk shortened ARNs to a
+// SingleResource type. Environments and application versions belong to
+// an application and keep it in the ID (environment/app/env) so they read
+// as app/env.
+func (aws *awsBeanstalk) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Chime shortened ARNs to a SingleResource type.
+// Users and channels live under their app instance
+// (app-instance/id/user/id) whose id goes into Details.
+func (aws *awsChime) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return childResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts WorkMail shortened ARNs (organization/m-id) to
+// a SingleResource type
+func (aws *awsWorkMail) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Application Auto Scaling shortened ARNs
+// (scalable-target/id) to a SingleResource type. The ARN doesn't name the
+// service namespace or resource being scaled, only the target's own id.
+func (aws *awsApplicationAutoScaling) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts CloudTrail shortened ARNs (trail/name) to a
+// SingleResource type. Multi-region trails only show up in their home region.
+func (aws *awsCloudTrail) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Greengrass shortened ARNs to a SingleResource
+// type. Greengrass v1 uses paths with a leading slash, /greengrass/groups/id
+// and /greengrass/definition/cores/id, whose definition type goes into
+// Details. Greengrass v2 ARNs (components:name, coreDevices:thing) are
+// plain type/id.
+func (aws *awsGreengrass) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	path := strings.TrimPrefix(*shortArn, "/greengrass/")
+	if path == *shortArn {
+		return typeAndIDResource(shortArn, svc, rgn)
+	}
+	s := strings.Split(path, "/")
+	if s[0] == "definition" && len(s) >= 3 {
+		return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &s[2], Details: &s[1]}
+	}
+	res := typeAndIDResource(&path, svc, rgn)
+	res.ARN = shortArn
+	return res
+}
+
+// ConvertToResource converts Panorama shortened ARNs (device/id, package/id,
+// app/id) to a SingleResource type
+func (aws *awsPanorama) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Forecast and Personalize shortened ARNs to a
+// SingleResource type. Most are type/name, resources that belong to another
+// one (dataset-import-job/dataset/job, dataset/group/INTERACTIONS) get their
+// parent in Details.
+func (aws *awsDatasetService) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return scopedResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Signer shortened ARNs to a SingleResource type.
+// Signing profiles are paths with a leading slash, /signing-profiles/name,
+// and specific versions of a profile (/signing-profiles/name/version) get
+// the version in Details.
+func (aws *awsSigner) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(strings.TrimPrefix(*shortArn, "/"), "/")
+	if len(s) == 3 {
+		return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &s[1], Details: &s[2]}
+	}
+	return pathResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Bedrock shortened ARNs (agent/id,
+// knowledge-base/id, guardrail/id, ...) to a SingleResource type. Agent
+// aliases (agent-alias/agent/alias) get their agent in Details, custom models
+// the model they're based on, whose id has colons of its own
+// (custom-model/amazon.titan-text-express-v1:0:8k/id).
+func (aws *awsBedrock) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	if s[0] == "custom-model" && len(s) > 2 {
+		base := strings.Join(s[1:len(s)-1], ":")
+		return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &s[len(s)-1], Details: &base}
+	}
+	return scopedResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Q Business shortened ARNs to a SingleResource
+// type. Indexes, retrievers and the like live under their application
+// (application/id/index/id) whose id goes into Details.
+func (aws *awsQBusiness) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return childResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Verified Permissions shortened ARNs to a
+// SingleResource type. Policies, templates and schemas live under their
+// policy store (policy-store/id/policy/id) whose id goes into Details. A
+// store has the one schema (policy-store/id/schema), it gets the store id.
+func (aws *awsVerifiedPermissions) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	if len(s) == 3 && s[0] == "policy-store" {
+		return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[2], ID: &s[1]}
+	}
+	return childResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Identity Store shortened ARNs (identitystore/d-id,
+// user/id, group/id) to a SingleResource type
+func (aws *awsIdentityStore) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Lake Formation shortened ARNs (catalog:account)
+// to a SingleResource type
+func (aws *awsLakeFormation) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts EventBridge Scheduler shortened ARNs to a
+// SingleResource type. Schedules belong to a group (schedule/group/name)
+// which goes into Details, groups themselves are schedule-group/name.
+func (aws *awsScheduler) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return scopedResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts End User Messaging SMS and voice shortened ARNs
+// (phone-number/id, pool/id, configuration-set/name, ...) to a
+// SingleResource type. Sender ids are registered per country
+// (sender-id/MySender/US) and get the country code in Details.
+func (aws *awsSMSVoice) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	if s[0] == "sender-id" && len(s) == 3 {
+		return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &s[1], Details: &s[2]}
+	}
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Route 53 Resolver shortened ARNs
+// (resolver-rule/id, resolver-endpoint/id, firewall-rule-group/id, ...) to a
+// SingleResource type
+func (aws *awsRoute53Resolver) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Detective shortened ARNs (graph/id, the ARN
+// separates them with a colon) to a SingleResource type
+func (aws *awsDetective) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts IAM Access Analyzer shortened ARNs to a
+// SingleResource type. Analyzers are analyzer/name, their archive rules
+// (analyzer/name/archive-rule/rule) get the analyzer in Details.
+func (aws *awsAccessAnalyzer) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return childResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Glue DataBrew shortened ARNs (recipe/name,
+// job/name, dataset/name, project/name, ruleset/name) to a SingleResource
+// type
+func (aws *awsDataBrew) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts EMR Serverless shortened ARNs to a
+// SingleResource type. Applications are /applications/id and their job runs
+// /applications/id/jobruns/id, which get the application id in Details.
+func (aws *awsEMRServerless) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	trimmed := strings.TrimPrefix(*shortArn, "/")
+	res := childResource(&trimmed, svc, rgn)
+	res.ARN = shortArn
+	return res
+}
+
+// ConvertToResource converts Resource Access Manager shortened ARNs
+// (resource-share/id, permission/name, resource-share-invitation/id) to a
+// SingleResource type
+func (aws *awsRAM) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return typeAndIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts Glue shortened ARNs to a SingleResource type.
+// Jobs, crawlers, databases and Schema Registry registries are type/name
+// (crawler/name), tables and schemas live in their database or registry
+// (table/db/name, schema/registry/name) which goes into Details.
+func (aws *awsGlue) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return scopedResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts MSK Connect shortened ARNs
+// (connector/name/uuid, custom-plugin/name/uuid,
+// worker-configuration/name/uuid) to a SingleResource type
+func (aws *awsKafkaConnect) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return nameAndUUIDResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts QLDB shortened ARNs to a SingleResource type.
+// Ledgers are ledger/name, journal streams live under their ledger
+// (stream/ledger/id) which goes into Details.
+func (aws *awsQLDB) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	return scopedResource(shortArn, svc, rgn)
+}
+
+// ConvertToResource converts WAFv2 shortened ARNs to a SingleResource type.
+// Web ACLs, IP sets, rule groups and the like are scope/type/name/id, the
+// scope being global for the CLOUDFRONT scope and regional otherwise. Their
+// ARNs always have a region, us-east-1 for CLOUDFRONT, so the scope is what
+// decides whether the Region is global. It goes into Details as WAF names
+// it, CLOUDFRONT or REGIONAL.
+func (aws *awsWAFv2) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.Split(*shortArn, "/")
+	if len(s) != 4 {
+		return typeAndIDResource(shortArn, svc, rgn)
+	}
+	scope, region := "REGIONAL", rgn
+	if s[0] == "global" {
+		global := globalRegion
+		scope, region = "CLOUDFRONT", &global
+	}
+	return &SingleResource{ARN: shortArn, Region: region, Service: svc, Product: &s[1], ID: &s[2], Details: &scope}
+}
+
+// typeAndIDResource handles the common "type/id" shortened ARN shape,
+// using the first segment as Product and everything after it as ID. ARNs
+// without a resource type just get the whole thing as ID.
+func typeAndIDResource(shortArn, svc, rgn *string) *SingleResource {
+	s := strings.SplitN(*shortArn, "/", 2)
+	if len(s) < 2 {
+		return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, ID: shortArn}
+	}
+	return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &s[1]}
+}
+
+// GetResourceRow shortens the ARN and assigns it to the right
+// service type calling its "ConvertToRow" method. Since we have
+// a default behaviour funneled towards our awsGeneric type, all
+// services will be handled.
+func ConvertArnToSingleResource(arn, svc, rgn *string) *SingleResource {
+	res := convertShortArn(ShortArn(arn), svc, rgn)
+
+	// The converters work off the short ARN, but we always want to hand
+	// back the full one so it can be fed straight into other tools
+	res.ARN = arn
+
+	// The account id is always the fifth segment of the ARN, although
+	// some resources (e.g. S3 buckets) leave it empty
+	s := strings.Split(*arn, ":")
+	if len(s) > 4 && s[4] != "" {
+		res.Account = &s[4]
+	}
+	if len(s) > 1 && s[1] != "" {
+		res.Partition = &s[1]
+	}
+	return res
+}
+
+// convertShortArn assigns the shortened ARN to the right service type
+func convertShortArn(shortArn string, svc, rgn *string) *SingleResource {
+	switch *svc {
+	case "ec2":
+		res := awsEC2(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "ssm":
+		res := awsSSM(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "ecs":
+		res := awsECS(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "rds":
+		return rdsConverter.ConvertToResource(&shortArn, svc, rgn)
+	case "athena":
+		res := awsAthena(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "quicksight":
+		res := awsQuickSight(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "medialive":
+		res := awsMediaLive(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "mediaconvert":
+		res := awsMediaConvert(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "transfer":
+		res := awsTransfer(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "datasync":
+		res := awsDataSync(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "guardduty":
+		res := awsGuardDuty(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "securityhub":
+		res := awsSecurityHub(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "codebuild":
+		res := awsCodeBuild(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "codepipeline":
+		res := awsCodePipeline(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "codecommit":
+		res := awsCodeCommit(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "ses":
+		res := awsSES(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "mobiletargeting":
+		res := awsPinpoint(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "globalaccelerator":
+		res := awsGlobalAccelerator(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "directconnect":
+		res := awsDirectConnect(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "macie2":
+		res := awsMacie(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "inspector2":
+		res := awsInspector(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "autoscaling":
+		res := awsAutoScaling(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "acm":
+		res := awsACM(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "acm-pca":
+		res := awsACMPCA(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "catalog", "servicecatalog":
+		res := awsServiceCatalog(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "resource-groups":
+		res := awsResourceGroups(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "redshift":
+		return redshiftConverter.ConvertToResource(&shortArn, svc, rgn)
+	case "timestream":
+		res := awsTimestream(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "kafka":
+		res := awsMSK(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "elasticmapreduce":
+		res := awsEMR(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "config":
+		res := awsConfig(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "amplify":
+		res := awsAmplify(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "apprunner":
+		res := awsAppRunner(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "lambda":
+		res := awsLambda(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "events":
+		res := awsEventBridge(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "sagemaker":
+		res := awsSageMaker(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "iot":
+		res := awsIoT(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "gamelift":
+		res := awsGameLift(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "comprehend", "translate", "rekognition":
+		res := awsAIService(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "mq":
+		res := awsMQ(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "memorydb":
+		res := awsMemoryDB(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "workspaces":
+		res := awsWorkSpaces(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "appstream":
+		res := awsAppStream(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "codeartifact":
+		res := awsCodeArtifact(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "codedeploy":
+		return codeDeployConverter.ConvertToResource(&shortArn, svc, rgn)
+	case "vpc-lattice":
+		res := awsVPCLattice(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "shield":
+		res := awsShield(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "network-firewall":
+		res := awsNetworkFirewall(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "lightsail":
+		res := awsLightsail(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "outposts":
+		res := awsOutposts(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "dms":
+		res := awsDMS(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "connect":
+		res := awsConnect(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "elasticache":
+		return elastiCacheConverter.ConvertToResource(&shortArn, svc, rgn)
+	case "sns":
+		return snsConverter.ConvertToResource(&shortArn, svc, rgn)
+	case "sqs":
+		return sqsConverter.ConvertToResource(&shortArn, svc, rgn)
+	case "states":
+		return stepFunctionsConverter.ConvertToResource(&shortArn, svc, rgn)
+	case "storagegateway":
+		res := awsStorageGateway(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "snowball":
+		res := awsSnowball(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "elasticbeanstalk":
+		res := awsBeanstalk(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "chime":
+		res := awsChime(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "workmail":
+		res := awsWorkMail(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "application-autoscaling":
+		res := awsApplicationAutoScaling(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "cloudtrail":
+		res := awsCloudTrail(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "greengrass":
+		res := awsGreengrass(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "panorama":
+		res := awsPanorama(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "forecast", "personalize":
+		res := awsDatasetService(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "signer":
+		res := awsSigner(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "bedrock":
+		res := awsBedrock(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "qbusiness":
+		res := awsQBusiness(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "verifiedpermissions":
+		res := awsVerifiedPermissions(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "identitystore":
+		res := awsIdentityStore(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "lakeformation":
+		res := awsLakeFormation(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "scheduler":
+		res := awsScheduler(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "sms-voice":
+		res := awsSMSVoice(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "route53resolver":
+		res := awsRoute53Resolver(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "detective":
+		res := awsDetective(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "access-analyzer":
+		res := awsAccessAnalyzer(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "databrew":
+		res := awsDataBrew(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "emr-serverless":
+		res := awsEMRServerless(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "ram":
+		res := awsRAM(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "glue":
+		res := awsGlue(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "kafkaconnect":
+		res := awsKafkaConnect(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "qldb":
+		res := awsQLDB(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "wafv2":
+		res := awsWAFv2(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	case "logs":
+		res := awsLogs(*svc)
+		return res.ConvertToResource(&shortArn, svc, rgn)
+	default:
+		res := awsGeneric(*svc)
+		return res.ConverToResource(&shortArn, svc, rgn)
+	}
+}
@@ -0,0 +1,665 @@
+package main
+
+import "testing"
+
+// testRegion is the region the sample ARNs are converted as if they were
+// listed from, and testAccount the account they all belong to
+const (
+	testRegion  = "us-east-1"
+	testAccount = "123456789012"
+)
+
+// noAccount is the account of a converterCase whose ARN has none, like S3
+// buckets
+const noAccount = "(none)"
+
+// converterCase is a sample ARN and the resource it should convert to.
+// Region, account and partition default to testRegion, testAccount and
+// aws, the ones nearly every sample has.
+type converterCase struct {
+	arn       string
+	service   string
+	product   string
+	id        string
+	details   string
+	region    string
+	account   string
+	partition string
+}
+
+// testConverters runs every case's ARN through ConvertArnToSingleResource
+// the way a scan of testRegion would, and checks what comes out
+func testConverters(t *testing.T, cases []converterCase) {
+	t.Helper()
+	for _, c := range cases {
+		c := c
+		t.Run(c.arn, func(t *testing.T) {
+			arn, region := c.arn, testRegion
+			r := ConvertArnToSingleResource(&arn, ServiceNameFromARN(&arn), &region)
+
+			want := c
+			if want.region == "" {
+				want.region = testRegion
+			}
+			switch want.account {
+			case "":
+				want.account = testAccount
+			case noAccount:
+				want.account = ""
+			}
+			if want.partition == "" {
+				want.partition = "aws"
+			}
+
+			got := converterCase{
+				arn:       DerefNilPointerStrings(r.ARN),
+				service:   DerefNilPointerStrings(r.Service),
+				product:   DerefNilPointerStrings(r.Product),
+				id:        DerefNilPointerStrings(r.ID),
+				details:   DerefNilPointerStrings(r.Details),
+				region:    DerefNilPointerStrings(r.Region),
+				account:   DerefNilPointerStrings(r.Account),
+				partition: DerefNilPointerStrings(r.Partition),
+			}
+			if got != want {
+				t.Errorf("got  %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLogsConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:logs:us-east-1:123456789012:log-group:/aws/lambda/my-fn:*", service: "logs", product: "log-group", id: "/aws/lambda/my-fn"},
+		{arn: "arn:aws:logs:us-east-1:123456789012:log-group:/ecs/prod/web/api:*", service: "logs", product: "log-group", id: "/ecs/prod/web/api"},
+		{arn: "arn:aws:logs:us-east-1:123456789012:log-group:/aws/eks/prod/cluster", service: "logs", product: "log-group", id: "/aws/eks/prod/cluster"},
+		{arn: "arn:aws:logs:us-east-1:123456789012:log-group:my-app", service: "logs", product: "log-group", id: "my-app"},
+		{arn: "arn:aws:logs:us-east-1:123456789012:destination:my-dest", service: "logs", product: "destination", id: "my-dest"},
+	})
+}
+
+func TestRDSConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:rds:us-east-1:123456789012:cluster:my-docdb", service: "rds", product: "cluster", id: "my-docdb"},
+		{arn: "arn:aws:rds:us-east-1:123456789012:db:my-docdb-instance-1", service: "rds", product: "db", id: "my-docdb-instance-1"},
+		{arn: "arn:aws:rds:us-east-1:123456789012:cluster:my-neptune", service: "rds", product: "cluster", id: "my-neptune"},
+		{arn: "arn:aws:rds:us-east-1:123456789012:db:my-neptune-1", service: "rds", product: "db", id: "my-neptune-1"},
+		{arn: "arn:aws:rds:us-east-1:123456789012:snapshot:rds:my-db-2024-01-01-00-00", service: "rds", product: "snapshot", id: "rds:my-db-2024-01-01-00-00"},
+	})
+}
+
+func TestAthenaQuickSightConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:athena:us-east-1:123456789012:workgroup/primary", service: "athena", product: "workgroup", id: "primary"},
+		{arn: "arn:aws:athena:us-east-1:123456789012:datacatalog/AwsDataCatalog", service: "athena", product: "datacatalog", id: "AwsDataCatalog"},
+		{arn: "arn:aws:quicksight:us-east-1:123456789012:dashboard/1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d", service: "quicksight", product: "dashboard", id: "1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d"},
+		{arn: "arn:aws:quicksight:us-east-1:123456789012:dataset/0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0", service: "quicksight", product: "dataset", id: "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0"},
+		{arn: "arn:aws:quicksight:us-east-1:123456789012:analysis/abcdef12-3456-7890-abcd-ef1234567890", service: "quicksight", product: "analysis", id: "abcdef12-3456-7890-abcd-ef1234567890"},
+	})
+}
+
+func TestTransferDataSyncConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:transfer:us-east-1:123456789012:server/s-0123456789abcdef0", service: "transfer", product: "server", id: "s-0123456789abcdef0"},
+		{arn: "arn:aws:transfer:us-east-1:123456789012:user/s-0123456789abcdef0/alice", service: "transfer", product: "user", id: "alice", details: "s-0123456789abcdef0"},
+		{arn: "arn:aws:transfer:us-east-1:123456789012:workflow/w-0123456789abcdef0", service: "transfer", product: "workflow", id: "w-0123456789abcdef0"},
+		{arn: "arn:aws:datasync:us-east-1:123456789012:task/task-0123456789abcdef0", service: "datasync", product: "task", id: "task-0123456789abcdef0"},
+		{arn: "arn:aws:datasync:us-east-1:123456789012:location/loc-0123456789abcdef0", service: "datasync", product: "location", id: "loc-0123456789abcdef0"},
+		{arn: "arn:aws:datasync:us-east-1:123456789012:agent/agent-0123456789abcdef0", service: "datasync", product: "agent", id: "agent-0123456789abcdef0"},
+	})
+}
+
+func TestEC2Converter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:ec2:us-east-1:123456789012:instance/i-0abc123", service: "ec2", product: "instance", id: "i-0abc123"},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:spot-instances-request/sir-abc123", service: "ec2", product: "spot-instances-request", id: "sir-abc123"},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-0abc123", service: "ec2", product: "network-interface", id: "eni-0abc123"},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:security-group/sg-0abc123", service: "ec2", product: "security-group", id: "sg-0abc123"},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0abc123", service: "ec2", product: "vpc", id: "vpc-0abc123"},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-0abc123", service: "ec2", product: "subnet", id: "subnet-0abc123"},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:volume/vol-0abc123", service: "ec2", product: "volume", id: "vol-0abc123"},
+		{arn: "arn:aws:ec2:us-east-1::snapshot/snap-0abc123", service: "ec2", product: "snapshot", id: "snap-0abc123", account: noAccount},
+		{arn: "arn:aws:ec2:us-east-1::image/ami-0abc123", service: "ec2", product: "image", id: "ami-0abc123", account: noAccount},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:fleet/fleet-0abc123", service: "ec2", product: "fleet", id: "fleet-0abc123"},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:launch-template/lt-0abc123", service: "ec2", product: "launch-template", id: "lt-0abc123"},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:natgateway/nat-0abc123", service: "ec2", product: "natgateway", id: "nat-0abc123"},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:internet-gateway/igw-0abc123", service: "ec2", product: "internet-gateway", id: "igw-0abc123"},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:route-table/rtb-0abc123", service: "ec2", product: "route-table", id: "rtb-0abc123"},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:weird", service: "ec2", id: "weird"},
+		{arn: "arn:aws:ssm:us-east-1:123456789012:managed-instance/mi-0123456789abcdef0", service: "ssm", product: "managed-instance", id: "mi-0123456789abcdef0"},
+	})
+}
+
+func TestDeveloperToolsConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:codebuild:us-east-1:123456789012:project/my-project", service: "codebuild", product: "project", id: "my-project"},
+		{arn: "arn:aws:codebuild:us-east-1:123456789012:report-group/my-reports", service: "codebuild", product: "report-group", id: "my-reports"},
+		{arn: "arn:aws:codepipeline:us-east-1:123456789012:my-pipeline", service: "codepipeline", product: "pipeline", id: "my-pipeline"},
+		{arn: "arn:aws:codecommit:us-east-1:123456789012:my-repo", service: "codecommit", product: "repository", id: "my-repo"},
+	})
+}
+
+func TestSESPinpointConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:ses:us-east-1:123456789012:identity/example.com", service: "ses", product: "identity", id: "example.com"},
+		{arn: "arn:aws:ses:us-east-1:123456789012:identity/alice@example.com", service: "ses", product: "identity", id: "alice@example.com"},
+		{arn: "arn:aws:ses:us-east-1:123456789012:configuration-set/my-set", service: "ses", product: "configuration-set", id: "my-set"},
+		{arn: "arn:aws:mobiletargeting:us-east-1:123456789012:apps/0123456789abcdef0123456789abcdef", service: "mobiletargeting", product: "apps", id: "0123456789abcdef0123456789abcdef"},
+		{arn: "arn:aws:mobiletargeting:us-east-1:123456789012:apps/0123456789abcdef0123456789abcdef/campaigns/fedcba9876543210fedcba9876543210", service: "mobiletargeting", product: "campaigns", id: "fedcba9876543210fedcba9876543210", details: "0123456789abcdef0123456789abcdef"},
+	})
+}
+
+func TestMacieInspectorConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:macie2:us-east-1:123456789012:classification-job/0123456789abcdef0123456789abcdef", service: "macie2", product: "classification-job", id: "0123456789abcdef0123456789abcdef"},
+		{arn: "arn:aws:macie2:us-east-1:123456789012:custom-data-identifier/12345678-1234-1234-1234-123456789012", service: "macie2", product: "custom-data-identifier", id: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:macie2:us-east-1:123456789012:allow-list/abcdefghij0123456789", service: "macie2", product: "allow-list", id: "abcdefghij0123456789"},
+		{arn: "arn:aws:inspector2:us-east-1:123456789012:finding/0123456789abcdef0123456789abcdef", service: "inspector2", product: "finding", id: "0123456789abcdef0123456789abcdef"},
+		{arn: "arn:aws:inspector2:us-east-1:123456789012:owner/123456789012/filter/abcd1234", service: "inspector2", product: "filter", id: "abcd1234", details: "123456789012"},
+	})
+}
+
+func TestAutoScalingConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:autoscaling:us-east-1:123456789012:autoScalingGroup:12345678-1234-1234-1234-123456789012:autoScalingGroupName/my-asg", service: "autoscaling", product: "autoScalingGroup", id: "my-asg", details: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:autoscaling:us-east-1:123456789012:scalingPolicy:12345678-1234-1234-1234-123456789012:autoScalingGroupName/my-asg:policyName/scale-out", service: "autoscaling", product: "scalingPolicy", id: "scale-out", details: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:autoscaling:us-east-1:123456789012:launchConfiguration:12345678-1234-1234-1234-123456789012:launchConfigurationName/my-lc", service: "autoscaling", product: "launchConfiguration", id: "my-lc", details: "12345678-1234-1234-1234-123456789012"},
+	})
+}
+
+func TestServiceCatalogResourceGroupsConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:catalog:us-east-1:123456789012:product/prod-abcdefghijklm", service: "catalog", product: "product", id: "prod-abcdefghijklm"},
+		{arn: "arn:aws:catalog:us-east-1:123456789012:portfolio/port-abcdefghijklm", service: "catalog", product: "portfolio", id: "port-abcdefghijklm"},
+		{arn: "arn:aws:servicecatalog:us-east-1:123456789012:/applications/0abcdefghijklmnop", service: "servicecatalog", product: "applications", id: "0abcdefghijklmnop"},
+		{arn: "arn:aws:resource-groups:us-east-1:123456789012:group/my-group", service: "resource-groups", product: "group", id: "my-group"},
+	})
+}
+
+func TestRedshiftTimestreamConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:redshift:us-east-1:123456789012:cluster:my-cluster", service: "redshift", product: "cluster", id: "my-cluster"},
+		{arn: "arn:aws:redshift:us-east-1:123456789012:snapshot:my-cluster/my-snapshot", service: "redshift", product: "snapshot", id: "my-snapshot", details: "my-cluster"},
+		{arn: "arn:aws:redshift:us-east-1:123456789012:parametergroup:my-params", service: "redshift", product: "parametergroup", id: "my-params"},
+		{arn: "arn:aws:timestream:us-east-1:123456789012:database/my-db", service: "timestream", product: "database", id: "my-db"},
+		{arn: "arn:aws:timestream:us-east-1:123456789012:database/my-db/table/my-table", service: "timestream", product: "table", id: "my-db.my-table"},
+	})
+}
+
+func TestMSKConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:kafka:us-east-1:123456789012:cluster/my-cluster/12345678-1234-1234-1234-123456789012-1", service: "kafka", product: "cluster", id: "my-cluster", details: "12345678-1234-1234-1234-123456789012-1"},
+		{arn: "arn:aws:kafka:us-east-1:123456789012:configuration/my-config/12345678-1234-1234-1234-123456789012-1", service: "kafka", product: "configuration", id: "my-config", details: "12345678-1234-1234-1234-123456789012-1"},
+		{arn: "arn:aws:kafka:us-east-1:123456789012:cluster/my-cluster", service: "kafka", product: "cluster", id: "my-cluster"},
+	})
+}
+
+// TestConverterGoldenFixtures runs a sample of every ARN shape the README
+// documents, plus the other partitions, through the converters. The
+// README table and these fixtures should be kept in step.
+func TestConverterGoldenFixtures(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:ec2:us-east-1:123456789012:instance/i-0abc123", service: "ec2", product: "instance", id: "i-0abc123"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:cluster/prod", service: "ecs", product: "cluster", id: "prod"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:service/prod/web", service: "ecs", product: "service", id: "web", details: "prod"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:task/prod/0123456789abcdef0123456789abcdef", service: "ecs", product: "task", id: "0123456789abcdef0123456789abcdef", details: "prod"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:task-definition/web:42", service: "ecs", product: "task-definition", id: "web", details: "42"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:task-set/prod/web/ecs-svc/1234567890123456789", service: "ecs", product: "task-set", id: "ecs-svc/1234567890123456789", details: "prod/web"},
+		{arn: "arn:aws:ssm:us-east-1:123456789012:managed-instance/mi-0123456789abcdef0", service: "ssm", product: "managed-instance", id: "mi-0123456789abcdef0"},
+		{arn: "arn:aws:logs:us-east-1:123456789012:log-group:/aws/lambda/my-fn:*", service: "logs", product: "log-group", id: "/aws/lambda/my-fn"},
+		{arn: "arn:aws:rds:us-east-1:123456789012:cluster:my-docdb", service: "rds", product: "cluster", id: "my-docdb"},
+		{arn: "arn:aws:rds:us-east-1:123456789012:db:my-neptune-1", service: "rds", product: "db", id: "my-neptune-1"},
+		{arn: "arn:aws:rds:us-east-1:123456789012:snapshot:rds:my-db-2024-01-01-00-00", service: "rds", product: "snapshot", id: "rds:my-db-2024-01-01-00-00"},
+		{arn: "arn:aws:athena:us-east-1:123456789012:workgroup/primary", service: "athena", product: "workgroup", id: "primary"},
+		{arn: "arn:aws:quicksight:us-east-1:123456789012:dashboard/5f2c1a9e", service: "quicksight", product: "dashboard", id: "5f2c1a9e"},
+		{arn: "arn:aws:medialive:us-east-1:123456789012:channel:123", service: "medialive", product: "channel", id: "123"},
+		{arn: "arn:aws:mediaconvert:us-east-1:123456789012:queues/Default", service: "mediaconvert", product: "queues", id: "Default"},
+		{arn: "arn:aws:transfer:us-east-1:123456789012:server/s-123", service: "transfer", product: "server", id: "s-123"},
+		{arn: "arn:aws:transfer:us-east-1:123456789012:user/s-123/alice", service: "transfer", product: "user", id: "alice", details: "s-123"},
+		{arn: "arn:aws:datasync:us-east-1:123456789012:task/task-0123", service: "datasync", product: "task", id: "task-0123"},
+		{arn: "arn:aws:guardduty:us-east-1:123456789012:detector/12abc34d/filter/my-filter", service: "guardduty", product: "filter", id: "my-filter", details: "12abc34d"},
+		{arn: "arn:aws:securityhub:us-east-1:123456789012:hub/default", service: "securityhub", product: "hub", id: "default"},
+		{arn: "arn:aws:codebuild:us-east-1:123456789012:project/my-project", service: "codebuild", product: "project", id: "my-project"},
+		{arn: "arn:aws:codepipeline:us-east-1:123456789012:my-pipeline", service: "codepipeline", product: "pipeline", id: "my-pipeline"},
+		{arn: "arn:aws:codecommit:us-east-1:123456789012:my-repo", service: "codecommit", product: "repository", id: "my-repo"},
+		{arn: "arn:aws:ses:us-east-1:123456789012:identity/example.com", service: "ses", product: "identity", id: "example.com"},
+		{arn: "arn:aws:mobiletargeting:us-east-1:123456789012:apps/abc123/campaigns/def456", service: "mobiletargeting", product: "campaigns", id: "def456", details: "abc123"},
+		{arn: "arn:aws:globalaccelerator::123456789012:accelerator/1234abcd", service: "globalaccelerator", product: "accelerator", id: "1234abcd", region: "global"},
+		{arn: "arn:aws:directconnect:us-east-1:123456789012:dxcon/dxcon-fg5678gh", service: "directconnect", product: "dxcon", id: "dxcon-fg5678gh"},
+		{arn: "arn:aws:macie2:us-east-1:123456789012:classification-job/3ce05dbb", service: "macie2", product: "classification-job", id: "3ce05dbb"},
+		{arn: "arn:aws:inspector2:us-east-1:123456789012:owner/123456789012/filter/abc", service: "inspector2", product: "filter", id: "abc", details: "123456789012"},
+		{arn: "arn:aws:autoscaling:us-east-1:123456789012:autoScalingGroup:6d4e36b3-3c1b-4d5e-a3b1-1d2f3e4a5b6c:autoScalingGroupName/my-asg", service: "autoscaling", product: "autoScalingGroup", id: "my-asg", details: "6d4e36b3-3c1b-4d5e-a3b1-1d2f3e4a5b6c"},
+		{arn: "arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789012", service: "acm", product: "certificate", id: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:acm-pca:us-east-1:123456789012:certificate-authority/12345678-1234-1234-1234-123456789012", service: "acm-pca", product: "certificate-authority", id: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:catalog:us-east-1:123456789012:product/prod-abcd1234", service: "catalog", product: "product", id: "prod-abcd1234"},
+		{arn: "arn:aws:servicecatalog:us-east-1:123456789012:/applications/0abc123", service: "servicecatalog", product: "applications", id: "0abc123"},
+		{arn: "arn:aws:resource-groups:us-east-1:123456789012:group/my-group", service: "resource-groups", product: "group", id: "my-group"},
+		{arn: "arn:aws:redshift:us-east-1:123456789012:snapshot:my-cluster/my-snapshot", service: "redshift", product: "snapshot", id: "my-snapshot", details: "my-cluster"},
+		{arn: "arn:aws:timestream:us-east-1:123456789012:database/db/table/tbl", service: "timestream", product: "table", id: "db.tbl"},
+		{arn: "arn:aws:kafka:us-east-1:123456789012:cluster/my-cluster/abcd1234-abcd-1234-abcd-1234abcd1234-1", service: "kafka", product: "cluster", id: "my-cluster", details: "abcd1234-abcd-1234-abcd-1234abcd1234-1"},
+		{arn: "arn:aws:elasticmapreduce:us-east-1:123456789012:cluster/j-ABCDEFGHIJKL", service: "elasticmapreduce", product: "cluster", id: "j-ABCDEFGHIJKL"},
+		{arn: "arn:aws:config:us-east-1:123456789012:config-rule/config-rule-ab12cd", service: "config", product: "config-rule", id: "config-rule-ab12cd"},
+		{arn: "arn:aws:config:us-east-1:123456789012:conformance-pack/my-pack/conformance-pack-ab12cd34", service: "config", product: "conformance-pack", id: "my-pack", details: "conformance-pack-ab12cd34"},
+		{arn: "arn:aws:config:us-east-1:123456789012:config-aggregator/config-aggregator-abcd1234", service: "config", product: "aggregator", id: "config-aggregator-abcd1234"},
+		{arn: "arn:aws:amplify:us-east-1:123456789012:apps/d1a2b3c4/branches/main", service: "amplify", product: "branches", id: "d1a2b3c4", details: "main"},
+		{arn: "arn:aws:apprunner:us-east-1:123456789012:service/my-service/8fe1e10304f84fd2b0df550fe98a71fa", service: "apprunner", product: "service", id: "my-service", details: "8fe1e10304f84fd2b0df550fe98a71fa"},
+		{arn: "arn:aws:lambda:us-east-1:123456789012:function:my-fn", service: "lambda", product: "function", id: "my-fn"},
+		{arn: "arn:aws:lambda:us-east-1:123456789012:function:my-fn:live", service: "lambda", product: "function", id: "my-fn", details: "live"},
+		{arn: "arn:aws:lambda:us-east-1:123456789012:layer:my-layer:3", service: "lambda", product: "layer", id: "my-layer", details: "3"},
+		{arn: "arn:aws:lambda:us-east-1:123456789012:event-source-mapping:a1b2c3d4-5678-90ab-cdef-11111EXAMPLE", service: "lambda", product: "event-source-mapping", id: "a1b2c3d4-5678-90ab-cdef-11111EXAMPLE"},
+		{arn: "arn:aws:events:us-east-1:123456789012:rule/nightly-backup", service: "events", product: "rule", id: "nightly-backup"},
+		{arn: "arn:aws:events:us-east-1:123456789012:rule/orders-bus/order-created", service: "events", product: "rule", id: "order-created", details: "orders-bus"},
+		{arn: "arn:aws:events:us-east-1:123456789012:event-bus/orders-bus", service: "events", product: "event-bus", id: "orders-bus"},
+		{arn: "arn:aws:events:us-east-1:123456789012:api-destination/my-dest/a1b2c3d4-5678-90ab-cdef-11111EXAMPLE", service: "events", product: "api-destination", id: "my-dest", details: "a1b2c3d4-5678-90ab-cdef-11111EXAMPLE"},
+		{arn: "arn:aws:sagemaker:us-east-1:123456789012:notebook-instance/my-notebook", service: "sagemaker", product: "notebook-instance", id: "my-notebook"},
+		{arn: "arn:aws:sagemaker:us-east-1:123456789012:training-job/my-training-job", service: "sagemaker", product: "training-job", id: "my-training-job"},
+		{arn: "arn:aws:iot:us-east-1:123456789012:thing/sensor-42", service: "iot", product: "thing", id: "sensor-42"},
+		{arn: "arn:aws:iot:us-east-1:123456789012:rule/forward_telemetry", service: "iot", product: "topicrule", id: "forward_telemetry"},
+		{arn: "arn:aws:gamelift:us-east-1:123456789012:fleet/fleet-2222bbbb-33cc-44dd-55ee-6666ffff77aa", service: "gamelift", product: "fleet", id: "fleet-2222bbbb-33cc-44dd-55ee-6666ffff77aa"},
+		{arn: "arn:aws:comprehend:us-east-1:123456789012:document-classifier/my-classifier/version/v1", service: "comprehend", product: "document-classifier", id: "my-classifier", details: "version/v1"},
+		{arn: "arn:aws:translate:us-east-1:123456789012:terminology/my-terms", service: "translate", product: "terminology", id: "my-terms"},
+		{arn: "arn:aws:rekognition:us-east-1:123456789012:project/my-project/1611857616051", service: "rekognition", product: "project", id: "my-project", details: "1611857616051"},
+		{arn: "arn:aws:mq:us-east-1:123456789012:broker:my-broker:b-1234a5b6-78cd-901e-2fgh-3i45j6k178l9", service: "mq", product: "broker", id: "my-broker", details: "b-1234a5b6-78cd-901e-2fgh-3i45j6k178l9"},
+		{arn: "arn:aws:mq:us-east-1:123456789012:configuration:c-1234a5b6-78cd-901e-2fgh-3i45j6k178l9", service: "mq", product: "configuration", id: "c-1234a5b6-78cd-901e-2fgh-3i45j6k178l9"},
+		{arn: "arn:aws:memorydb:us-east-1:123456789012:cluster/my-cluster", service: "memorydb", product: "cluster", id: "my-cluster"},
+		{arn: "arn:aws:workspaces:us-east-1:123456789012:workspace/ws-abc123def", service: "workspaces", product: "workspace", id: "ws-abc123def"},
+		{arn: "arn:aws:appstream:us-east-1:123456789012:fleet/my-fleet", service: "appstream", product: "fleet", id: "my-fleet"},
+		{arn: "arn:aws:codeartifact:us-east-1:123456789012:repository/my-domain/my-repo", service: "codeartifact", product: "repository", id: "my-repo", details: "my-domain"},
+		{arn: "arn:aws:codedeploy:us-east-1:123456789012:application:my-app", service: "codedeploy", product: "application", id: "my-app"},
+		{arn: "arn:aws:codedeploy:us-east-1:123456789012:deploymentgroup:my-app/my-group", service: "codedeploy", product: "deploymentgroup", id: "my-group", details: "my-app"},
+		{arn: "arn:aws:vpc-lattice:us-east-1:123456789012:service/svc-0285b53b2eEXAMPLE", service: "vpc-lattice", product: "service", id: "svc-0285b53b2eEXAMPLE"},
+		{arn: "arn:aws:shield::123456789012:protection/a1b2c3d4-5678-90ab-cdef-EXAMPLE11111", service: "shield", product: "protection", id: "a1b2c3d4-5678-90ab-cdef-EXAMPLE11111", region: "global"},
+		{arn: "arn:aws:network-firewall:us-east-1:123456789012:firewall/my-firewall", service: "network-firewall", product: "firewall", id: "my-firewall"},
+		{arn: "arn:aws:network-firewall:us-east-1:123456789012:stateful-rulegroup/my-rules", service: "network-firewall", product: "rulegroup", id: "my-rules", details: "stateful"},
+		{arn: "arn:aws:lightsail:us-east-1:123456789012:Instance/244ad76f-8aad-4741-809f-12345EXAMPLE", service: "lightsail", product: "instance", id: "244ad76f-8aad-4741-809f-12345EXAMPLE"},
+		{arn: "arn:aws:lightsail:us-east-1:123456789012:RelationalDatabase/12345678-1234-1234-1234-123456789012", service: "lightsail", product: "database", id: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:outposts:us-east-1:123456789012:outpost/op-0abcd1234efgh5678", service: "outposts", product: "outpost", id: "op-0abcd1234efgh5678"},
+		{arn: "arn:aws:dms:us-east-1:123456789012:rep:ABCDEFGHIJKLMNOPQRSTUVWXYZ", service: "dms", product: "replication-instance", id: "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+		{arn: "arn:aws:dms:us-east-1:123456789012:endpoint:ZYXWVUTSRQPONMLKJIHGFEDCBA", service: "dms", product: "endpoint", id: "ZYXWVUTSRQPONMLKJIHGFEDCBA"},
+		{arn: "arn:aws:dms:us-east-1:123456789012:task:2PVREMWNPGYJCVU2IBPTOYTIV4", service: "dms", product: "task", id: "2PVREMWNPGYJCVU2IBPTOYTIV4"},
+		{arn: "arn:aws:connect:us-east-1:123456789012:instance/a1b2c3d4-5678-90ab-cdef-EXAMPLE11111", service: "connect", product: "instance", id: "a1b2c3d4-5678-90ab-cdef-EXAMPLE11111"},
+		{arn: "arn:aws:connect:us-east-1:123456789012:instance/a1b2c3d4-5678-90ab-cdef-EXAMPLE11111/queue/q-0123456789", service: "connect", product: "queue", id: "q-0123456789", details: "a1b2c3d4-5678-90ab-cdef-EXAMPLE11111"},
+		{arn: "arn:aws:connect:us-east-1:123456789012:instance/a1b2c3d4-5678-90ab-cdef-EXAMPLE11111/contact-flow/cf-0123456789", service: "connect", product: "contact-flow", id: "cf-0123456789", details: "a1b2c3d4-5678-90ab-cdef-EXAMPLE11111"},
+		{arn: "arn:aws:elasticache:us-east-1:123456789012:replicationgroup:my-redis", service: "elasticache", product: "replicationgroup", id: "my-redis"},
+		{arn: "arn:aws:sns:us-east-1:123456789012:my-topic", service: "sns", product: "topic", id: "my-topic"},
+		{arn: "arn:aws:sqs:us-east-1:123456789012:my-queue", service: "sqs", product: "queue", id: "my-queue"},
+		{arn: "arn:aws:states:us-east-1:123456789012:stateMachine:my-state-machine", service: "states", product: "stateMachine", id: "my-state-machine"},
+		{arn: "arn:aws:storagegateway:us-east-1:123456789012:gateway/sgw-12A3456B", service: "storagegateway", product: "gateway", id: "sgw-12A3456B"},
+		{arn: "arn:aws:storagegateway:us-east-1:123456789012:gateway/sgw-12A3456B/volume/vol-1122AABB", service: "storagegateway", product: "volume", id: "vol-1122AABB", details: "sgw-12A3456B"},
+		{arn: "arn:aws:snowball:us-east-1:123456789012:job/JID123e4567-e89b-12d3-a456-426655440000", service: "snowball", product: "job", id: "JID123e4567-e89b-12d3-a456-426655440000"},
+		{arn: "arn:aws:snowball:us-east-1:123456789012:cluster/CID123e4567-e89b-12d3-a456-426655440000", service: "snowball", product: "cluster", id: "CID123e4567-e89b-12d3-a456-426655440000"},
+		{arn: "arn:aws:elasticbeanstalk:us-east-1:123456789012:application/my-app", service: "elasticbeanstalk", product: "application", id: "my-app"},
+		{arn: "arn:aws:elasticbeanstalk:us-east-1:123456789012:environment/my-app/my-app-prod", service: "elasticbeanstalk", product: "environment", id: "my-app/my-app-prod"},
+		{arn: "arn:aws-us-gov:ec2:us-gov-west-1:123456789012:instance/i-0abc123", service: "ec2", product: "instance", id: "i-0abc123", partition: "aws-us-gov"},
+		{arn: "arn:aws:chime:us-east-1:123456789012:app-instance/abcd1234/user/5678efgh", service: "chime", product: "user", id: "5678efgh", details: "abcd1234"},
+		{arn: "arn:aws:workmail:us-east-1:123456789012:organization/m-d281d0a2fd824be5b6cd3d3ce909fd27", service: "workmail", product: "organization", id: "m-d281d0a2fd824be5b6cd3d3ce909fd27"},
+		{arn: "arn:aws:application-autoscaling:us-east-1:123456789012:scalable-target/0ec51e2bdd8bbf0e1b4bd0c6a8e1a8ad63f8", service: "application-autoscaling", product: "scalable-target", id: "0ec51e2bdd8bbf0e1b4bd0c6a8e1a8ad63f8"},
+		{arn: "arn:aws:cloudtrail:us-east-1:123456789012:trail/management-events", service: "cloudtrail", product: "trail", id: "management-events"},
+		{arn: "arn:aws:greengrass:us-east-1:123456789012:/greengrass/groups/4ad66e5e-3f93-4d1b-ab1f-1e1e9b5bca0d", service: "greengrass", product: "groups", id: "4ad66e5e-3f93-4d1b-ab1f-1e1e9b5bca0d"},
+		{arn: "arn:aws:greengrass:us-east-1:123456789012:/greengrass/definition/cores/0cb0e3e4-2bd0-4e4b-9a16-58f7e1ad3e8b", service: "greengrass", product: "definition", id: "0cb0e3e4-2bd0-4e4b-9a16-58f7e1ad3e8b", details: "cores"},
+		{arn: "arn:aws:greengrass:us-east-1:123456789012:components:com.example.HelloWorld", service: "greengrass", product: "components", id: "com.example.HelloWorld"},
+		{arn: "arn:aws:panorama:us-east-1:123456789012:device/device-abcd1234", service: "panorama", product: "device", id: "device-abcd1234"},
+		{arn: "arn:aws:forecast:us-east-1:123456789012:dataset/sales", service: "forecast", product: "dataset", id: "sales"},
+		{arn: "arn:aws:forecast:us-east-1:123456789012:dataset-import-job/sales/sales_import", service: "forecast", product: "dataset-import-job", id: "sales_import", details: "sales"},
+		{arn: "arn:aws:personalize:us-east-1:123456789012:dataset-group/retail", service: "personalize", product: "dataset-group", id: "retail"},
+		{arn: "arn:aws:personalize:us-east-1:123456789012:dataset/retail/INTERACTIONS", service: "personalize", product: "dataset", id: "INTERACTIONS", details: "retail"},
+		{arn: "arn:aws:signer:us-east-1:123456789012:/signing-profiles/MyProfile", service: "signer", product: "signing-profiles", id: "MyProfile"},
+		{arn: "arn:aws:signer:us-east-1:123456789012:/signing-profiles/MyProfile/ABCDEF1234", service: "signer", product: "signing-profiles", id: "MyProfile", details: "ABCDEF1234"},
+		{arn: "arn:aws:ec2::123456789012:ipam/ipam-08440e7a3acde3908", service: "ec2", product: "ipam", id: "ipam-08440e7a3acde3908"},
+		{arn: "arn:aws:ec2::123456789012:ipam-pool/ipam-pool-07ccc86aa41bef7ce", service: "ec2", product: "ipam-pool", id: "ipam-pool-07ccc86aa41bef7ce"},
+		{arn: "arn:aws:ec2::123456789012:ipam-scope/ipam-scope-0b9eed026396dbc16", service: "ec2", product: "ipam-scope", id: "ipam-scope-0b9eed026396dbc16"},
+		{arn: "arn:aws:bedrock:us-east-1:123456789012:agent/AGENT12345", service: "bedrock", product: "agent", id: "AGENT12345"},
+		{arn: "arn:aws:bedrock:us-east-1:123456789012:agent-alias/AGENT12345/ALIAS12345", service: "bedrock", product: "agent-alias", id: "ALIAS12345", details: "AGENT12345"},
+		{arn: "arn:aws:bedrock:us-east-1:123456789012:knowledge-base/KB12345678", service: "bedrock", product: "knowledge-base", id: "KB12345678"},
+		{arn: "arn:aws:bedrock:us-east-1:123456789012:custom-model/amazon.titan-text-express-v1:0:8k/a1b2c3d4e5f6", service: "bedrock", product: "custom-model", id: "a1b2c3d4e5f6", details: "amazon.titan-text-express-v1:0:8k"},
+		{arn: "arn:aws:bedrock:us-east-1:123456789012:guardrail/gr1234abcd", service: "bedrock", product: "guardrail", id: "gr1234abcd"},
+		{arn: "arn:aws:qbusiness:us-east-1:123456789012:application/app-id/index/index-id", service: "qbusiness", product: "index", id: "index-id", details: "app-id"},
+		{arn: "arn:aws:verifiedpermissions::123456789012:policy-store/PSEXAMPLEabcdefg111111", service: "verifiedpermissions", product: "policy-store", id: "PSEXAMPLEabcdefg111111"},
+		{arn: "arn:aws:verifiedpermissions::123456789012:policy-store/PSEXAMPLEabcdefg111111/policy/SPEXAMPLEabcdefg111111", service: "verifiedpermissions", product: "policy", id: "SPEXAMPLEabcdefg111111", details: "PSEXAMPLEabcdefg111111"},
+		{arn: "arn:aws:identitystore::123456789012:identitystore/d-1234567890", service: "identitystore", product: "identitystore", id: "d-1234567890"},
+		{arn: "arn:aws:lakeformation:us-east-1:123456789012:catalog:123456789012", service: "lakeformation", product: "catalog", id: "123456789012"},
+		{arn: "arn:aws:scheduler:us-east-1:123456789012:schedule/default/nightly-report", service: "scheduler", product: "schedule", id: "nightly-report", details: "default"},
+		{arn: "arn:aws:scheduler:us-east-1:123456789012:schedule-group/reports", service: "scheduler", product: "schedule-group", id: "reports"},
+		{arn: "arn:aws:sms-voice:us-east-1:123456789012:phone-number/phone-1234567890abcdef", service: "sms-voice", product: "phone-number", id: "phone-1234567890abcdef"},
+		{arn: "arn:aws:sms-voice:us-east-1:123456789012:pool/pool-1234567890abcdef", service: "sms-voice", product: "pool", id: "pool-1234567890abcdef"},
+		{arn: "arn:aws:sms-voice:us-east-1:123456789012:configuration-set/alerts", service: "sms-voice", product: "configuration-set", id: "alerts"},
+		{arn: "arn:aws:sms-voice:us-east-1:123456789012:sender-id/MySender/GB", service: "sms-voice", product: "sender-id", id: "MySender", details: "GB"},
+		{arn: "arn:aws:route53resolver:us-east-1:123456789012:resolver-rule/rslvr-rr-5328a0899aexample", service: "route53resolver", product: "resolver-rule", id: "rslvr-rr-5328a0899aexample"},
+		{arn: "arn:aws:route53resolver:us-east-1:123456789012:resolver-endpoint/rslvr-in-60b9fd8fdbexample", service: "route53resolver", product: "resolver-endpoint", id: "rslvr-in-60b9fd8fdbexample"},
+		{arn: "arn:aws:route53resolver:us-east-1:123456789012:firewall-rule-group/rslvr-frg-47f93271fexample", service: "route53resolver", product: "firewall-rule-group", id: "rslvr-frg-47f93271fexample"},
+		{arn: "arn:aws:detective:us-east-1:123456789012:graph:abcd1234", service: "detective", product: "graph", id: "abcd1234"},
+		{arn: "arn:aws:access-analyzer:us-east-1:123456789012:analyzer/org-analyzer", service: "access-analyzer", product: "analyzer", id: "org-analyzer"},
+		{arn: "arn:aws:access-analyzer:us-east-1:123456789012:analyzer/org-analyzer/archive-rule/ignore-public", service: "access-analyzer", product: "archive-rule", id: "ignore-public", details: "org-analyzer"},
+		{arn: "arn:aws:databrew:us-east-1:123456789012:recipe/clean-orders", service: "databrew", product: "recipe", id: "clean-orders"},
+		{arn: "arn:aws:databrew:us-east-1:123456789012:job/nightly-profile", service: "databrew", product: "job", id: "nightly-profile"},
+		{arn: "arn:aws:emr-serverless:us-east-1:123456789012:/applications/00f1abcd2345", service: "emr-serverless", product: "applications", id: "00f1abcd2345"},
+		{arn: "arn:aws:emr-serverless:us-east-1:123456789012:/applications/00f1abcd2345/jobruns/00f1efgh6789", service: "emr-serverless", product: "jobruns", id: "00f1efgh6789", details: "00f1abcd2345"},
+		{arn: "arn:aws:ram:us-east-1:123456789012:resource-share/7ab63972-b505-7e2a-420d-6f5d3EXAMPLE", service: "ram", product: "resource-share", id: "7ab63972-b505-7e2a-420d-6f5d3EXAMPLE"},
+		{arn: "arn:aws:ram:us-east-1:123456789012:permission/SubnetSharing", service: "ram", product: "permission", id: "SubnetSharing"},
+		{arn: "arn:aws:glue:us-east-1:123456789012:registry/orders", service: "glue", product: "registry", id: "orders"},
+		{arn: "arn:aws:glue:us-east-1:123456789012:schema/orders/order-created", service: "glue", product: "schema", id: "order-created", details: "orders"},
+		{arn: "arn:aws:glue:us-east-1:123456789012:table/sales/orders", service: "glue", product: "table", id: "orders", details: "sales"},
+		{arn: "arn:aws:kafkaconnect:us-east-1:123456789012:connector/s3-sink/a1b2c3d4-5678-90ab-cdef-EXAMPLE11111-2", service: "kafkaconnect", product: "connector", id: "s3-sink", details: "a1b2c3d4-5678-90ab-cdef-EXAMPLE11111-2"},
+		{arn: "arn:aws:glue:us-east-1:123456789012:crawler/nightly-sales", service: "glue", product: "crawler", id: "nightly-sales"},
+		{arn: "arn:aws:qldb:us-east-1:123456789012:ledger/payments", service: "qldb", product: "ledger", id: "payments"},
+		{arn: "arn:aws:qldb:us-east-1:123456789012:stream/payments/IiPT4brpZCqCq3f4MTHbYy", service: "qldb", product: "stream", id: "IiPT4brpZCqCq3f4MTHbYy", details: "payments"},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:reserved-instances/0bf2ef3e-4c2a-4f0e-9a3b-EXAMPLE", service: "ec2", product: "reserved-instances", id: "0bf2ef3e-4c2a-4f0e-9a3b-EXAMPLE"},
+		{arn: "arn:aws:rds:us-east-1:123456789012:ri:my-reservation", service: "rds", product: "ri", id: "my-reservation"},
+		{arn: "arn:aws:wafv2:us-east-1:123456789012:global/webacl/cdn-acl/a1b2c3d4-5678-90ab-cdef-EXAMPLE11111", service: "wafv2", product: "webacl", id: "cdn-acl", details: "CLOUDFRONT", region: "global"},
+		{arn: "arn:aws:wafv2:eu-west-1:123456789012:regional/webacl/alb-acl/a1b2c3d4-5678-90ab-cdef-EXAMPLE22222", service: "wafv2", product: "webacl", id: "alb-acl", details: "REGIONAL"},
+		{arn: "arn:aws:wafv2:eu-west-1:123456789012:regional/ipset/blocked/a1b2c3d4-5678-90ab-cdef-EXAMPLE33333", service: "wafv2", product: "ipset", id: "blocked", details: "REGIONAL"},
+		{arn: "arn:aws:s3:::my-bucket", service: "s3", id: "my-bucket", account: noAccount},
+		{arn: "arn:aws-cn:ec2:cn-north-1:123456789012:instance/i-0abc123", service: "ec2", product: "instance", id: "i-0abc123", partition: "aws-cn"},
+		{arn: "arn:aws-us-gov:iam::123456789012:role/admin", service: "iam", id: "role/admin", partition: "aws-us-gov"},
+		{arn: "arn:aws-us-gov:s3:::gov-bucket", service: "s3", id: "gov-bucket", account: noAccount, partition: "aws-us-gov"},
+	})
+}
+
+func TestAmplifyAppRunnerConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:amplify:us-east-1:123456789012:apps/d1abcdefgh1234", service: "amplify", product: "apps", id: "d1abcdefgh1234"},
+		{arn: "arn:aws:amplify:us-east-1:123456789012:apps/d1abcdefgh1234/branches/main", service: "amplify", product: "branches", id: "d1abcdefgh1234", details: "main"},
+		{arn: "arn:aws:amplify:us-east-1:123456789012:apps/d1abcdefgh1234/branches/feature/login", service: "amplify", product: "branches", id: "d1abcdefgh1234", details: "feature/login"},
+		{arn: "arn:aws:apprunner:us-east-1:123456789012:service/my-api/0123456789abcdef0123456789abcdef", service: "apprunner", product: "service", id: "my-api", details: "0123456789abcdef0123456789abcdef"},
+	})
+}
+
+func TestLambdaConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:lambda:us-east-1:123456789012:function:my-fn", service: "lambda", product: "function", id: "my-fn"},
+		{arn: "arn:aws:lambda:us-east-1:123456789012:function:my-fn:live", service: "lambda", product: "function", id: "my-fn", details: "live"},
+		{arn: "arn:aws:lambda:us-east-1:123456789012:function:my-fn:7", service: "lambda", product: "function", id: "my-fn", details: "7"},
+		{arn: "arn:aws:lambda:us-east-1:123456789012:layer:my-layer:3", service: "lambda", product: "layer", id: "my-layer", details: "3"},
+		{arn: "arn:aws:lambda:us-east-1:123456789012:layer:my-layer", service: "lambda", product: "layer", id: "my-layer"},
+		{arn: "arn:aws:lambda:us-east-1:123456789012:event-source-mapping:12345678-1234-1234-1234-123456789012", service: "lambda", product: "event-source-mapping", id: "12345678-1234-1234-1234-123456789012"},
+	})
+}
+
+func TestEventBridgeConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:events:us-east-1:123456789012:rule/my-rule", service: "events", product: "rule", id: "my-rule"},
+		{arn: "arn:aws:events:us-east-1:123456789012:rule/my-bus/my-rule", service: "events", product: "rule", id: "my-rule", details: "my-bus"},
+		{arn: "arn:aws:events:us-east-1:123456789012:event-bus/my-bus", service: "events", product: "event-bus", id: "my-bus"},
+		{arn: "arn:aws:events:us-east-1:123456789012:event-bus/default", service: "events", product: "event-bus", id: "default"},
+		{arn: "arn:aws:events:us-east-1:123456789012:api-destination/my-dest/12345678-1234-1234-1234-123456789012", service: "events", product: "api-destination", id: "my-dest", details: "12345678-1234-1234-1234-123456789012"},
+	})
+}
+
+func TestSageMakerConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:sagemaker:us-east-1:123456789012:notebook-instance/my-notebook", service: "sagemaker", product: "notebook-instance", id: "my-notebook"},
+		{arn: "arn:aws:sagemaker:us-east-1:123456789012:endpoint/my-endpoint", service: "sagemaker", product: "endpoint", id: "my-endpoint"},
+		{arn: "arn:aws:sagemaker:us-east-1:123456789012:endpoint-config/my-endpoint-config", service: "sagemaker", product: "endpoint-config", id: "my-endpoint-config"},
+		{arn: "arn:aws:sagemaker:us-east-1:123456789012:model/my-model", service: "sagemaker", product: "model", id: "my-model"},
+		{arn: "arn:aws:sagemaker:us-east-1:123456789012:training-job/my-training-job", service: "sagemaker", product: "training-job", id: "my-training-job"},
+		{arn: "arn:aws:sagemaker:us-east-1:123456789012:model-package/my-group/3", service: "sagemaker", product: "model-package", id: "my-group/3"},
+		{arn: "arn:aws:sagemaker:us-east-1:123456789012:model-package-group/my-group", service: "sagemaker", product: "model-package-group", id: "my-group"},
+	})
+}
+
+func TestIoTGameLiftConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:iot:us-east-1:123456789012:thing/sensor-42", service: "iot", product: "thing", id: "sensor-42"},
+		{arn: "arn:aws:iot:us-east-1:123456789012:policy/my-policy", service: "iot", product: "policy", id: "my-policy"},
+		{arn: "arn:aws:iot:us-east-1:123456789012:rule/forward_telemetry", service: "iot", product: "topicrule", id: "forward_telemetry"},
+		{arn: "arn:aws:iot:us-east-1:123456789012:cert/0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", service: "iot", product: "certificate", id: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"},
+		{arn: "arn:aws:iot:us-east-1:123456789012:thinggroup/sensors", service: "iot", product: "thinggroup", id: "sensors"},
+		{arn: "arn:aws:gamelift:us-east-1:123456789012:fleet/fleet-12345678-1234-1234-1234-123456789012", service: "gamelift", product: "fleet", id: "fleet-12345678-1234-1234-1234-123456789012"},
+	})
+}
+
+func TestCodeArtifactCodeDeployConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:codeartifact:us-east-1:123456789012:domain/my-domain", service: "codeartifact", product: "domain", id: "my-domain"},
+		{arn: "arn:aws:codeartifact:us-east-1:123456789012:repository/my-domain/my-repo", service: "codeartifact", product: "repository", id: "my-repo", details: "my-domain"},
+		{arn: "arn:aws:codedeploy:us-east-1:123456789012:application:my-app", service: "codedeploy", product: "application", id: "my-app"},
+		{arn: "arn:aws:codedeploy:us-east-1:123456789012:deploymentgroup:my-app/my-group", service: "codedeploy", product: "deploymentgroup", id: "my-group", details: "my-app"},
+		{arn: "arn:aws:codedeploy:us-east-1:123456789012:deploymentconfig:CodeDeployDefault.OneAtATime", service: "codedeploy", product: "deploymentconfig", id: "CodeDeployDefault.OneAtATime"},
+	})
+}
+
+func TestLightsailOutpostsConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:lightsail:us-east-1:123456789012:Instance/244ad76f-8aad-4741-809f-12345EXAMPLE", service: "lightsail", product: "instance", id: "244ad76f-8aad-4741-809f-12345EXAMPLE"},
+		{arn: "arn:aws:lightsail:us-east-1:123456789012:RelationalDatabase/12345678-1234-1234-1234-123456789012", service: "lightsail", product: "database", id: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:lightsail:us-east-1:123456789012:RelationalDatabaseSnapshot/12345678-1234-1234-1234-123456789012", service: "lightsail", product: "databasesnapshot", id: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:lightsail:us-east-1:123456789012:LoadBalancer/12345678-1234-1234-1234-123456789012", service: "lightsail", product: "loadbalancer", id: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:lightsail:us-east-1:123456789012:LoadBalancerTlsCertificate/12345678-1234-1234-1234-123456789012", service: "lightsail", product: "loadbalancercertificate", id: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:lightsail:us-east-1:123456789012:StaticIp/12345678-1234-1234-1234-123456789012", service: "lightsail", product: "staticip", id: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:outposts:us-east-1:123456789012:outpost/op-0123456789abcdef0", service: "outposts", product: "outpost", id: "op-0123456789abcdef0"},
+		{arn: "arn:aws:outposts:us-east-1:123456789012:site/os-0123456789abcdef0", service: "outposts", product: "site", id: "os-0123456789abcdef0"},
+	})
+}
+
+func TestDMSConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:dms:us-east-1:123456789012:rep:ABCDEFGHIJKLMNOPQRSTUVWXYZ", service: "dms", product: "replication-instance", id: "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+		{arn: "arn:aws:dms:us-east-1:123456789012:endpoint:ABCDEFGHIJKLMNOPQRSTUVWXYZ", service: "dms", product: "endpoint", id: "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+		{arn: "arn:aws:dms:us-east-1:123456789012:task:ABCDEFGHIJKLMNOPQRSTUVWXYZ", service: "dms", product: "task", id: "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+		{arn: "arn:aws:dms:us-east-1:123456789012:subgrp:my-subnet-group", service: "dms", product: "subnet-group", id: "my-subnet-group"},
+		{arn: "arn:aws:dms:us-east-1:123456789012:cert:ABCDEFGHIJKLMNOPQRSTUVWXYZ", service: "dms", product: "certificate", id: "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+	})
+}
+
+func TestConnectConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:connect:us-east-1:123456789012:instance/12345678-1234-1234-1234-123456789012", service: "connect", product: "instance", id: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:connect:us-east-1:123456789012:instance/12345678-1234-1234-1234-123456789012/queue/abcdef12-3456-7890-abcd-ef1234567890", service: "connect", product: "queue", id: "abcdef12-3456-7890-abcd-ef1234567890", details: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:connect:us-east-1:123456789012:instance/12345678-1234-1234-1234-123456789012/contact-flow/abcdef12-3456-7890-abcd-ef1234567890", service: "connect", product: "contact-flow", id: "abcdef12-3456-7890-abcd-ef1234567890", details: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:connect:us-east-1:123456789012:instance/12345678-1234-1234-1234-123456789012/routing-profile/abcdef12-3456-7890-abcd-ef1234567890", service: "connect", product: "routing-profile", id: "abcdef12-3456-7890-abcd-ef1234567890", details: "12345678-1234-1234-1234-123456789012"},
+	})
+}
+
+func TestStorageGatewaySnowballConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:storagegateway:us-east-1:123456789012:gateway/sgw-12A3456B", service: "storagegateway", product: "gateway", id: "sgw-12A3456B"},
+		{arn: "arn:aws:storagegateway:us-east-1:123456789012:gateway/sgw-12A3456B/volume/vol-1122AABB", service: "storagegateway", product: "volume", id: "vol-1122AABB", details: "sgw-12A3456B"},
+		{arn: "arn:aws:storagegateway:us-east-1:123456789012:tape/AMZNC8A26D", service: "storagegateway", product: "tape", id: "AMZNC8A26D"},
+		{arn: "arn:aws:snowball:us-east-1:123456789012:job/JID123e4567-e89b-12d3-a456-426655440000", service: "snowball", product: "job", id: "JID123e4567-e89b-12d3-a456-426655440000"},
+		{arn: "arn:aws:snowball:us-east-1:123456789012:cluster/CID123e4567-e89b-12d3-a456-426655440000", service: "snowball", product: "cluster", id: "CID123e4567-e89b-12d3-a456-426655440000"},
+	})
+}
+
+func TestBeanstalkConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:elasticbeanstalk:us-east-1:123456789012:application/my-app", service: "elasticbeanstalk", product: "application", id: "my-app"},
+		{arn: "arn:aws:elasticbeanstalk:us-east-1:123456789012:environment/my-app/my-app-prod", service: "elasticbeanstalk", product: "environment", id: "my-app/my-app-prod"},
+		{arn: "arn:aws:elasticbeanstalk:us-east-1:123456789012:applicationversion/my-app/v1.2.3", service: "elasticbeanstalk", product: "applicationversion", id: "my-app/v1.2.3"},
+		{arn: "arn:aws:elasticbeanstalk:us-east-1::platform/Python 3.11 running on 64bit Amazon Linux 2023/4.0.1", service: "elasticbeanstalk", product: "platform", id: "Python 3.11 running on 64bit Amazon Linux 2023/4.0.1", account: noAccount},
+	})
+}
+
+func TestGreengrassPanoramaConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:greengrass:us-east-1:123456789012:/greengrass/groups/4ad66e5e-3f93-4d1b-ab1f-1e1e9b5bca0d", service: "greengrass", product: "groups", id: "4ad66e5e-3f93-4d1b-ab1f-1e1e9b5bca0d"},
+		{arn: "arn:aws:greengrass:us-east-1:123456789012:/greengrass/definition/cores/0cb0e3e4-2bd0-4e4b-9a16-58f7e1ad3e8b", service: "greengrass", product: "definition", id: "0cb0e3e4-2bd0-4e4b-9a16-58f7e1ad3e8b", details: "cores"},
+		{arn: "arn:aws:greengrass:us-east-1:123456789012:/greengrass/definition/functions/7e3e1e5a-1d8b-4c2a-9f0e-2b3c4d5e6f7a", service: "greengrass", product: "definition", id: "7e3e1e5a-1d8b-4c2a-9f0e-2b3c4d5e6f7a", details: "functions"},
+		{arn: "arn:aws:greengrass:us-east-1:123456789012:components:com.example.HelloWorld", service: "greengrass", product: "components", id: "com.example.HelloWorld"},
+		{arn: "arn:aws:greengrass:us-east-1:123456789012:coreDevices:MyCoreDevice", service: "greengrass", product: "coreDevices", id: "MyCoreDevice"},
+		{arn: "arn:aws:panorama:us-east-1:123456789012:device/device-abcd1234", service: "panorama", product: "device", id: "device-abcd1234"},
+		{arn: "arn:aws:panorama:us-east-1:123456789012:package/package-abcd1234", service: "panorama", product: "package", id: "package-abcd1234"},
+		{arn: "arn:aws:panorama:us-east-1:123456789012:app/applicationInstance-abcd1234", service: "panorama", product: "app", id: "applicationInstance-abcd1234"},
+	})
+}
+
+func TestACMPCASignerConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:acm-pca:us-east-1:123456789012:certificate-authority/12345678-1234-1234-1234-123456789012", service: "acm-pca", product: "certificate-authority", id: "12345678-1234-1234-1234-123456789012"},
+		{arn: "arn:aws:signer:us-east-1:123456789012:/signing-profiles/MyProfile", service: "signer", product: "signing-profiles", id: "MyProfile"},
+		{arn: "arn:aws:signer:us-east-1:123456789012:/signing-profiles/MyProfile/ABCDEF1234", service: "signer", product: "signing-profiles", id: "MyProfile", details: "ABCDEF1234"},
+		{arn: "arn:aws:signer:us-east-1:123456789012:/signing-jobs/2c0a8b6e-1f3d-4e5a-9b7c-8d6e5f4a3b2c", service: "signer", product: "signing-jobs", id: "2c0a8b6e-1f3d-4e5a-9b7c-8d6e5f4a3b2c"},
+	})
+}
+
+func TestECSConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:ecs:us-east-1:123456789012:cluster/prod", service: "ecs", product: "cluster", id: "prod"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:service/prod/web", service: "ecs", product: "service", id: "web", details: "prod"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:task/prod/0123456789abcdef0123456789abcdef", service: "ecs", product: "task", id: "0123456789abcdef0123456789abcdef", details: "prod"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:task-definition/web:42", service: "ecs", product: "task-definition", id: "web", details: "42"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:task-set/prod/web/ecs-svc/1234567890123456789", service: "ecs", product: "task-set", id: "ecs-svc/1234567890123456789", details: "prod/web"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:container-instance/prod/0123456789abcdef0123456789abcdef", service: "ecs", product: "container-instance", id: "0123456789abcdef0123456789abcdef", details: "prod"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:capacity-provider/my-provider", service: "ecs", product: "capacity-provider", id: "my-provider"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:service/web", service: "ecs", product: "service", id: "web"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:task/0123456789abcdef0123456789abcdef", service: "ecs", product: "task", id: "0123456789abcdef0123456789abcdef"},
+		{arn: "arn:aws:ecs:us-east-1:123456789012:container-instance/0123456789abcdef0123456789abcdef", service: "ecs", product: "container-instance", id: "0123456789abcdef0123456789abcdef"},
+	})
+}
+
+func TestConfigConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:config:us-east-1:123456789012:config-rule/config-rule-ab12cd", service: "config", product: "config-rule", id: "config-rule-ab12cd"},
+		{arn: "arn:aws:config:us-east-1:123456789012:config-aggregator/config-aggregator-abcd1234", service: "config", product: "aggregator", id: "config-aggregator-abcd1234"},
+		{arn: "arn:aws:config:us-east-1:123456789012:conformance-pack/my-pack/conformance-pack-ab12cd34", service: "config", product: "conformance-pack", id: "my-pack", details: "conformance-pack-ab12cd34"},
+	})
+}
+
+func TestMQMemoryDBConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:mq:us-east-1:123456789012:broker:my-broker:b-1234a5b6-78cd-901e-2fgh-3i45j6k178l9", service: "mq", product: "broker", id: "my-broker", details: "b-1234a5b6-78cd-901e-2fgh-3i45j6k178l9"},
+		{arn: "arn:aws:mq:us-east-1:123456789012:configuration:c-1234a5b6-78cd-901e-2fgh-3i45j6k178l9", service: "mq", product: "configuration", id: "c-1234a5b6-78cd-901e-2fgh-3i45j6k178l9"},
+		{arn: "arn:aws:memorydb:us-east-1:123456789012:cluster/my-cluster", service: "memorydb", product: "cluster", id: "my-cluster"},
+		{arn: "arn:aws:memorydb:us-east-1:123456789012:user/my-user", service: "memorydb", product: "user", id: "my-user"},
+		{arn: "arn:aws:memorydb:us-east-1:123456789012:acl/my-acl", service: "memorydb", product: "acl", id: "my-acl"},
+		{arn: "arn:aws:memorydb:us-east-1:123456789012:snapshot/my-snapshot", service: "memorydb", product: "snapshot", id: "my-snapshot"},
+		{arn: "arn:aws:memorydb:us-east-1:123456789012:parametergroup/my-params", service: "memorydb", product: "parametergroup", id: "my-params"},
+	})
+}
+
+func TestShieldNetworkFirewallConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:shield::123456789012:protection/a1b2c3d4-5678-90ab-cdef-EXAMPLE11111", service: "shield", product: "protection", id: "a1b2c3d4-5678-90ab-cdef-EXAMPLE11111", region: "global"},
+		{arn: "arn:aws:shield::123456789012:protection-group/my-group", service: "shield", product: "protection-group", id: "my-group", region: "global"},
+		{arn: "arn:aws:network-firewall:us-east-1:123456789012:firewall/my-firewall", service: "network-firewall", product: "firewall", id: "my-firewall"},
+		{arn: "arn:aws:network-firewall:us-east-1:123456789012:firewall-policy/my-policy", service: "network-firewall", product: "firewall-policy", id: "my-policy"},
+		{arn: "arn:aws:network-firewall:us-east-1:123456789012:stateful-rulegroup/my-rules", service: "network-firewall", product: "rulegroup", id: "my-rules", details: "stateful"},
+		{arn: "arn:aws:network-firewall:us-east-1:123456789012:stateless-rulegroup/my-rules", service: "network-firewall", product: "rulegroup", id: "my-rules", details: "stateless"},
+	})
+}
+
+func TestForecastPersonalizeConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:forecast:us-east-1:123456789012:dataset/sales", service: "forecast", product: "dataset", id: "sales"},
+		{arn: "arn:aws:forecast:us-east-1:123456789012:dataset-group/retail-sales", service: "forecast", product: "dataset-group", id: "retail-sales"},
+		{arn: "arn:aws:forecast:us-east-1:123456789012:dataset-import-job/sales/sales_import", service: "forecast", product: "dataset-import-job", id: "sales_import", details: "sales"},
+		{arn: "arn:aws:forecast:us-east-1:123456789012:predictor/sales_predictor", service: "forecast", product: "predictor", id: "sales_predictor"},
+		{arn: "arn:aws:forecast:us-east-1:123456789012:forecast/sales_forecast", service: "forecast", product: "forecast", id: "sales_forecast"},
+		{arn: "arn:aws:personalize:us-east-1:123456789012:dataset-group/retail", service: "personalize", product: "dataset-group", id: "retail"},
+		{arn: "arn:aws:personalize:us-east-1:123456789012:dataset/retail/INTERACTIONS", service: "personalize", product: "dataset", id: "INTERACTIONS", details: "retail"},
+		{arn: "arn:aws:personalize:us-east-1:123456789012:solution/retail-recs", service: "personalize", product: "solution", id: "retail-recs"},
+		{arn: "arn:aws:personalize:us-east-1:123456789012:campaign/retail-campaign", service: "personalize", product: "campaign", id: "retail-campaign"},
+	})
+}
+
+func TestVerifiedPermissionsIdentityStoreConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:verifiedpermissions::123456789012:policy-store/PSEXAMPLEabcdefg111111", service: "verifiedpermissions", product: "policy-store", id: "PSEXAMPLEabcdefg111111"},
+		{arn: "arn:aws:verifiedpermissions::123456789012:policy-store/PSEXAMPLEabcdefg111111/policy/SPEXAMPLEabcdefg111111", service: "verifiedpermissions", product: "policy", id: "SPEXAMPLEabcdefg111111", details: "PSEXAMPLEabcdefg111111"},
+		{arn: "arn:aws:verifiedpermissions::123456789012:policy-store/PSEXAMPLEabcdefg111111/policy-template/PTEXAMPLEabcdefg111111", service: "verifiedpermissions", product: "policy-template", id: "PTEXAMPLEabcdefg111111", details: "PSEXAMPLEabcdefg111111"},
+		{arn: "arn:aws:verifiedpermissions::123456789012:policy-store/PSEXAMPLEabcdefg111111/schema", service: "verifiedpermissions", product: "schema", id: "PSEXAMPLEabcdefg111111"},
+		{arn: "arn:aws:identitystore::123456789012:identitystore/d-1234567890", service: "identitystore", product: "identitystore", id: "d-1234567890"},
+		{arn: "arn:aws:identitystore:::user/94482488-3041-7026-18f3-be45837cd0e4", service: "identitystore", product: "user", id: "94482488-3041-7026-18f3-be45837cd0e4", account: noAccount},
+		{arn: "arn:aws:identitystore:::group/a1b2c3d4-5678-90ab-cdef-EXAMPLE11111", service: "identitystore", product: "group", id: "a1b2c3d4-5678-90ab-cdef-EXAMPLE11111", account: noAccount},
+	})
+}
+
+func TestSchedulerConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:scheduler:us-east-1:123456789012:schedule/default/nightly-report", service: "scheduler", product: "schedule", id: "nightly-report", details: "default"},
+		{arn: "arn:aws:scheduler:us-east-1:123456789012:schedule/reports/weekly-summary", service: "scheduler", product: "schedule", id: "weekly-summary", details: "reports"},
+		{arn: "arn:aws:scheduler:us-east-1:123456789012:schedule-group/reports", service: "scheduler", product: "schedule-group", id: "reports"},
+		{arn: "arn:aws:scheduler:us-east-1:123456789012:schedule-group/default", service: "scheduler", product: "schedule-group", id: "default"},
+	})
+}
+
+func TestRoute53ResolverConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:route53resolver:us-east-1:123456789012:resolver-rule/rslvr-rr-5328a0899aexample", service: "route53resolver", product: "resolver-rule", id: "rslvr-rr-5328a0899aexample"},
+		{arn: "arn:aws:route53resolver:us-east-1:123456789012:resolver-endpoint/rslvr-in-60b9fd8fdbexample", service: "route53resolver", product: "resolver-endpoint", id: "rslvr-in-60b9fd8fdbexample"},
+		{arn: "arn:aws:route53resolver:us-east-1:123456789012:firewall-rule-group/rslvr-frg-47f93271fexample", service: "route53resolver", product: "firewall-rule-group", id: "rslvr-frg-47f93271fexample"},
+		{arn: "arn:aws:route53resolver:us-east-1:123456789012:firewall-domain-list/rslvr-fdl-2c46f2ecbexample", service: "route53resolver", product: "firewall-domain-list", id: "rslvr-fdl-2c46f2ecbexample"},
+		{arn: "arn:aws:route53resolver:us-east-1:123456789012:resolver-query-log-config/rslvr-rqlc-0123456789example", service: "route53resolver", product: "resolver-query-log-config", id: "rslvr-rqlc-0123456789example"},
+		{arn: "arn:aws:route53resolver:us-east-1:123456789012:firewall-rule-group-association/rslvr-frgassoc-57e8873d7example", service: "route53resolver", product: "firewall-rule-group-association", id: "rslvr-frgassoc-57e8873d7example"},
+	})
+}
+
+func TestDetectiveAccessAnalyzerConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:detective:us-east-1:123456789012:graph:abcd1234", service: "detective", product: "graph", id: "abcd1234"},
+		{arn: "arn:aws:detective:eu-west-1:123456789012:graph:0123456789abcdef0123456789abcdef", service: "detective", product: "graph", id: "0123456789abcdef0123456789abcdef"},
+		{arn: "arn:aws:access-analyzer:us-east-1:123456789012:analyzer/org-analyzer", service: "access-analyzer", product: "analyzer", id: "org-analyzer"},
+		{arn: "arn:aws:access-analyzer:us-east-1:123456789012:analyzer/org-analyzer/archive-rule/ignore-public", service: "access-analyzer", product: "archive-rule", id: "ignore-public", details: "org-analyzer"},
+		{arn: "arn:aws:access-analyzer:us-east-1:123456789012:analyzer/account-analyzer/archive-rule/trusted-accounts", service: "access-analyzer", product: "archive-rule", id: "trusted-accounts", details: "account-analyzer"},
+	})
+}
+
+func TestDataBrewEMRServerlessConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:databrew:us-east-1:123456789012:recipe/clean-orders", service: "databrew", product: "recipe", id: "clean-orders"},
+		{arn: "arn:aws:databrew:us-east-1:123456789012:job/nightly-profile", service: "databrew", product: "job", id: "nightly-profile"},
+		{arn: "arn:aws:databrew:us-east-1:123456789012:dataset/orders", service: "databrew", product: "dataset", id: "orders"},
+		{arn: "arn:aws:databrew:us-east-1:123456789012:project/orders-cleanup", service: "databrew", product: "project", id: "orders-cleanup"},
+		{arn: "arn:aws:databrew:us-east-1:123456789012:ruleset/orders-quality", service: "databrew", product: "ruleset", id: "orders-quality"},
+		{arn: "arn:aws:emr-serverless:us-east-1:123456789012:/applications/00f1abcd2345", service: "emr-serverless", product: "applications", id: "00f1abcd2345"},
+		{arn: "arn:aws:emr-serverless:us-east-1:123456789012:/applications/00f1abcd2345/jobruns/00f1efgh6789", service: "emr-serverless", product: "jobruns", id: "00f1efgh6789", details: "00f1abcd2345"},
+	})
+}
+
+func TestRAMConverter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:ram:us-east-1:123456789012:resource-share/7ab63972-b505-7e2a-420d-6f5d3EXAMPLE", service: "ram", product: "resource-share", id: "7ab63972-b505-7e2a-420d-6f5d3EXAMPLE"},
+		{arn: "arn:aws:ram:us-east-1:123456789012:permission/SubnetSharing", service: "ram", product: "permission", id: "SubnetSharing"},
+		{arn: "arn:aws:ram::aws:permission/AWSRAMDefaultPermissionSubnet", service: "ram", product: "permission", id: "AWSRAMDefaultPermissionSubnet", account: "aws"},
+		{arn: "arn:aws:ram:us-east-1:123456789012:resource-share-invitation/a1b2c3d4-5678-90ab-cdef-EXAMPLE11111", service: "ram", product: "resource-share-invitation", id: "a1b2c3d4-5678-90ab-cdef-EXAMPLE11111"},
+	})
+}
+
+func TestGlueSchemaRegistryMSKConnectConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:glue:us-east-1:123456789012:registry/orders", service: "glue", product: "registry", id: "orders"},
+		{arn: "arn:aws:glue:us-east-1:123456789012:schema/orders/order-created", service: "glue", product: "schema", id: "order-created", details: "orders"},
+		{arn: "arn:aws:glue:us-east-1:123456789012:table/sales/orders", service: "glue", product: "table", id: "orders", details: "sales"},
+		{arn: "arn:aws:glue:us-east-1:123456789012:database/sales", service: "glue", product: "database", id: "sales"},
+		{arn: "arn:aws:glue:us-east-1:123456789012:job/nightly-etl", service: "glue", product: "job", id: "nightly-etl"},
+		{arn: "arn:aws:kafkaconnect:us-east-1:123456789012:connector/s3-sink/a1b2c3d4-5678-90ab-cdef-EXAMPLE11111-2", service: "kafkaconnect", product: "connector", id: "s3-sink", details: "a1b2c3d4-5678-90ab-cdef-EXAMPLE11111-2"},
+		{arn: "arn:aws:kafkaconnect:us-east-1:123456789012:custom-plugin/s3-plugin/a1b2c3d4-5678-90ab-cdef-EXAMPLE22222-3", service: "kafkaconnect", product: "custom-plugin", id: "s3-plugin", details: "a1b2c3d4-5678-90ab-cdef-EXAMPLE22222-3"},
+		{arn: "arn:aws:kafkaconnect:us-east-1:123456789012:worker-configuration/tuned/a1b2c3d4-5678-90ab-cdef-EXAMPLE33333-4", service: "kafkaconnect", product: "worker-configuration", id: "tuned", details: "a1b2c3d4-5678-90ab-cdef-EXAMPLE33333-4"},
+	})
+}
+
+func TestGlueCrawlerQLDBConverters(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:glue:us-east-1:123456789012:crawler/nightly-sales", service: "glue", product: "crawler", id: "nightly-sales"},
+		{arn: "arn:aws:glue:us-east-1:123456789012:crawler/raw-events", service: "glue", product: "crawler", id: "raw-events"},
+		{arn: "arn:aws:qldb:us-east-1:123456789012:ledger/payments", service: "qldb", product: "ledger", id: "payments"},
+		{arn: "arn:aws:qldb:us-east-1:123456789012:stream/payments/IiPT4brpZCqCq3f4MTHbYy", service: "qldb", product: "stream", id: "IiPT4brpZCqCq3f4MTHbYy", details: "payments"},
+	})
+}
+
+func TestWAFv2Converter(t *testing.T) {
+	testConverters(t, []converterCase{
+		{arn: "arn:aws:wafv2:us-east-1:123456789012:global/webacl/cdn-acl/a1b2c3d4-5678-90ab-cdef-EXAMPLE11111", service: "wafv2", product: "webacl", id: "cdn-acl", details: "CLOUDFRONT", region: "global"},
+		{arn: "arn:aws:wafv2:us-east-1:123456789012:regional/webacl/alb-acl/a1b2c3d4-5678-90ab-cdef-EXAMPLE22222", service: "wafv2", product: "webacl", id: "alb-acl", details: "REGIONAL"},
+		{arn: "arn:aws:wafv2:us-east-1:123456789012:global/ipset/blocked/a1b2c3d4-5678-90ab-cdef-EXAMPLE33333", service: "wafv2", product: "ipset", id: "blocked", details: "CLOUDFRONT", region: "global"},
+		{arn: "arn:aws:wafv2:us-east-1:123456789012:regional/rulegroup/common/a1b2c3d4-5678-90ab-cdef-EXAMPLE44444", service: "wafv2", product: "rulegroup", id: "common", details: "REGIONAL"},
+		{arn: "arn:aws:wafv2:us-east-1:123456789012:regional/regexpatternset/bots/a1b2c3d4-5678-90ab-cdef-EXAMPLE55555", service: "wafv2", product: "regexpatternset", id: "bots", details: "REGIONAL"},
+	})
+}
+
+func TestWAFv2ScopeIsGlobalOrRegional(t *testing.T) {
+	tests := []struct {
+		arn      string
+		regional bool
+	}{
+		{"arn:aws:wafv2:us-east-1:123456789012:global/webacl/cdn-acl/a1b2c3d4-5678-90ab-cdef-EXAMPLE11111", false},
+		{"arn:aws:wafv2:us-east-1:123456789012:global/ipset/blocked/a1b2c3d4-5678-90ab-cdef-EXAMPLE33333", false},
+		{"arn:aws:wafv2:us-east-1:123456789012:regional/webacl/alb-acl/a1b2c3d4-5678-90ab-cdef-EXAMPLE22222", true},
+		{"arn:aws:wafv2:eu-west-1:123456789012:regional/rulegroup/common/a1b2c3d4-5678-90ab-cdef-EXAMPLE44444", true},
+	}
+	for _, tt := range tests {
+		arn := tt.arn
+		if got := HasARNRegion(&SingleResource{ARN: &arn}); got != tt.regional {
+			t.Errorf("HasARNRegion(%s) = %v, want %v", tt.arn, got, tt.regional)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// hoursPerMonth is the average month AWS bills hourly prices over
+const hoursPerMonth = 730
+
+// instanceHourlyPrices are rough on-demand Linux prices in USD per hour in
+// us-east-1 for the burstable instance types, which don't scale evenly
+// with their size
+var instanceHourlyPrices = map[string]float64{
+	"t2.nano": 0.0058, "t2.micro": 0.0116, "t2.small": 0.023, "t2.medium": 0.0464, "t2.large": 0.0928, "t2.xlarge": 0.1856, "t2.2xlarge": 0.3712,
+	"t3.nano": 0.0052, "t3.micro": 0.0104, "t3.small": 0.0208, "t3.medium": 0.0416, "t3.large": 0.0832, "t3.xlarge": 0.1664, "t3.2xlarge": 0.3328,
+	"t3a.nano": 0.0047, "t3a.micro": 0.0094, "t3a.small": 0.0188, "t3a.medium": 0.0376, "t3a.large": 0.0752, "t3a.xlarge": 0.1504, "t3a.2xlarge": 0.3008,
+	"t4g.nano": 0.0042, "t4g.micro": 0.0084, "t4g.small": 0.0168, "t4g.medium": 0.0336, "t4g.large": 0.0672, "t4g.xlarge": 0.1344, "t4g.2xlarge": 0.2688,
+}
+
+// largeHourlyPrices are the same rough prices for the .large size of the
+// general purpose, compute and memory optimized families, the other sizes
+// cost proportionally more or less
+var largeHourlyPrices = map[string]float64{
+	"m5": 0.096, "m5a": 0.086, "m6i": 0.096, "m6a": 0.0864, "m6g": 0.077, "m7g": 0.0816, "m7i": 0.1008,
+	"c5": 0.085, "c5a": 0.077, "c6i": 0.085, "c6a": 0.0765, "c6g": 0.068, "c7g": 0.0725, "c7i": 0.0893,
+	"r5": 0.126, "r5a": 0.113, "r6i": 0.126, "r6a": 0.1134, "r6g": 0.1008, "r7g": 0.1071, "r7i": 0.1323,
+}
+
+// sizeMultipliers are how many .large instances each size is worth
+var sizeMultipliers = map[string]float64{
+	"medium": 0.5, "large": 1, "xlarge": 2, "2xlarge": 4, "4xlarge": 8, "8xlarge": 16,
+	"12xlarge": 24, "16xlarge": 32, "24xlarge": 48, "32xlarge": 64, "48xlarge": 96,
+}
+
+// volumeMonthlyPrices are rough EBS prices in USD per GB-month in
+// us-east-1, leaving out provisioned IOPS and throughput
+var volumeMonthlyPrices = map[string]float64{
+	"gp3": 0.08, "gp2": 0.10, "io1": 0.125, "io2": 0.125, "st1": 0.045, "sc1": 0.015, "standard": 0.05,
+}
+
+// InstanceHourlyPrice returns the rough on-demand hourly price of an EC2
+// instance type, and false for types it doesn't know
+func InstanceHourlyPrice(instanceType string) (float64, bool) {
+	if p, ok := instanceHourlyPrices[instanceType]; ok {
+		return p, true
+	}
+	s := strings.SplitN(instanceType, ".", 2)
+	if len(s) != 2 {
+		return 0, false
+	}
+	large, ok := largeHourlyPrices[s[0]]
+	multiplier, known := sizeMultipliers[s[1]]
+	if !ok || !known {
+		return 0, false
+	}
+	return large * multiplier, true
+}
+
+// CostEstimator attaches a rough monthly cost to the resources it knows
+// how to price, for --estimate-cost. It only covers running EC2 instances
+// (by instance type) and EBS volumes (by type and size), priced at
+// us-east-1 on-demand rates whatever their region, without discounts,
+// licenses or data transfer. Like the Enricher it describes each region
+// once and caches the result.
+type CostEstimator struct {
+	cfg aws.Config
+
+	// costs maps region to instance and volume ids to their monthly cost
+	costs map[string]map[string]float64
+}
+
+// NewCostEstimator returns a CostEstimator making its calls with cfg
+func NewCostEstimator(cfg aws.Config) *CostEstimator {
+	return &CostEstimator{cfg: cfg, costs: map[string]map[string]float64{}}
+}
+
+// Estimate sets the MonthlyCost of r when it's a resource it can price
+func (c *CostEstimator) Estimate(ctx context.Context, r *SingleResource) {
+	if DerefNilPointerStrings(r.Service) != "ec2" {
+		return
+	}
+	product := DerefNilPointerStrings(r.Product)
+	if product != "instance" && product != "volume" {
+		return
+	}
+	region := DerefNilPointerStrings(r.Region)
+	costs, ok := c.costs[region]
+	if !ok {
+		var err error
+		if costs, err = describeEC2Costs(ctx, c.cfg, region); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: --estimate-cost can't describe EC2 in %s: %v\n", region, err)
+		}
+		c.costs[region] = costs
+	}
+	if cost, ok := costs[DerefNilPointerStrings(r.ID)]; ok {
+		monthly := fmt.Sprintf("%.2f", cost)
+		r.MonthlyCost = &monthly
+	}
+}
+
+// describeEC2Costs prices the instances and volumes of the region,
+// returning a map of their ids to their monthly cost. Instances that
+// aren't running cost nothing, their volumes are priced on their own.
+func describeEC2Costs(ctx context.Context, cfg aws.Config, region string) (map[string]float64, error) {
+	client := ec2.NewFromConfig(cfg, func(o *ec2.Options) {
+		o.Region = region
+	})
+	costs := map[string]float64{}
+
+	instances := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{})
+	for instances.HasMorePages() {
+		out, err := instances.NextPage(ctx)
+		if err != nil {
+			return costs, err
+		}
+		for _, reservation := range out.Reservations {
+			for _, i := range reservation.Instances {
+				if i.State == nil || i.State.Name != ec2types.InstanceStateNameRunning {
+					costs[aws.ToString(i.InstanceId)] = 0
+					continue
+				}
+				if hourly, ok := InstanceHourlyPrice(string(i.InstanceType)); ok {
+					costs[aws.ToString(i.InstanceId)] = hourly * hoursPerMonth
+				}
+			}
+		}
+	}
+
+	volumes := ec2.NewDescribeVolumesPaginator(client, &ec2.DescribeVolumesInput{})
+	for volumes.HasMorePages() {
+		out, err := volumes.NextPage(ctx)
+		if err != nil {
+			return costs, err
+		}
+		for _, v := range out.Volumes {
+			if perGB, ok := volumeMonthlyPrices[string(v.VolumeType)]; ok && v.Size != nil {
+				costs[aws.ToString(v.VolumeId)] = perGB * float64(*v.Size)
+			}
+		}
+	}
+	return costs, nil
+}
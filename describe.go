@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// resourceFields are the keys of a resource in the JSON output, in the
+// order SingleResource declares them
+var resourceFields = []string{"partition", "region", "account", "accountName", "service", "serviceCode", "product", "details", "id", "arn", "tags", "estimatedMonthlyCost"}
+
+// DescribeOutput writes the shape of the output without any resources in
+// it, for --describe-output: the header of table and csv output, the
+// columns of line output, the keys of logfmt output, and a JSON Schema of the json and jsonl output.
+// It takes the same columns and field renames as the real output.
+func DescribeOutput(w io.Writer, output string, columns []string, fields FieldMap, flatten, pretty bool) error {
+	switch output {
+	case "table":
+		PrettyPrintResources(w, nil, columns)
+		return nil
+	case "line":
+		_, err := fmt.Fprintln(w, strings.Join(columns, "/"))
+		return err
+	case "logfmt":
+		keys := make([]string, len(columns))
+		for i, c := range columns {
+			keys[i] = logfmtKey(c) + "="
+		}
+		_, err := fmt.Fprintln(w, strings.Join(keys, " "))
+		return err
+	case "csv", "csv-per-service":
+		return RenderCSV(w, nil, columns, fields, false)
+	case "tags-csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"arn", "tag_key", "tag_value"})
+		cw.Flush()
+		return cw.Error()
+	case "jsonl":
+		schema := resourceSchema(fields, flatten)
+		schema["$schema"] = jsonSchemaDraft
+		return jsonEncoder(w, pretty).Encode(schema)
+	case "json":
+		schema := map[string]interface{}{
+			"$schema": jsonSchemaDraft,
+			"type":    "object",
+			"properties": map[string]interface{}{
+				"schemaVersion": map[string]interface{}{"const": OutputSchemaVersion},
+				"resources":     map[string]interface{}{"type": "array", "items": resourceSchema(fields, flatten)},
+				"errors": map[string]interface{}{"type": "array", "items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"region":       map[string]string{"type": "string"},
+						"error":        map[string]string{"type": "string"},
+						"blockedBySCP": map[string]string{"type": "boolean"},
+					},
+				}},
+			},
+			"required": []string{"schemaVersion", "resources"},
+		}
+		return jsonEncoder(w, pretty).Encode(schema)
+	}
+	return fmt.Errorf("--describe-output only describes table, line, logfmt, csv, csv-per-service, tags-csv, json and jsonl output, not %q", output)
+}
+
+// jsonSchemaDraft is the JSON Schema version DescribeOutput writes
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// resourceSchema returns the JSON Schema of a single resource as the json
+// and jsonl output write it. Every field is optional, empty ones are left
+// out.
+func resourceSchema(fields FieldMap, flatten bool) map[string]interface{} {
+	properties := map[string]interface{}{}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	for _, f := range resourceFields {
+		if f == "tags" && flatten {
+			continue
+		}
+		name := f
+		if to, ok := fields[f]; ok {
+			name = to
+		}
+		if f == "tags" {
+			properties[name] = map[string]interface{}{"type": "object", "additionalProperties": map[string]string{"type": "string"}}
+		} else {
+			properties[name] = map[string]string{"type": "string"}
+		}
+	}
+	if flatten {
+		schema["patternProperties"] = map[string]interface{}{"^tag_": map[string]string{"type": "string"}}
+	}
+	return schema
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sagemaker"
+	sagemakertypes "github.com/aws/aws-sdk-go-v2/service/sagemaker/types"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+)
+
+// Enricher fills in details the ARNs don't carry with extra describe
+// calls, for --enrich. Lookups are cached, per region where a single call
+// covers a whole region, and one that fails is reported once and leaves
+// the resources as they are.
+type Enricher struct {
+	cfg aws.Config
+
+	// rdsEngines maps region to DB instance and cluster ARNs to their
+	// engine
+	rdsEngines map[string]map[string]string
+	// bucketRegions maps S3 bucket names to the region they're in, the
+	// tagging API returns buckets from every region scanned
+	bucketRegions map[string]string
+	// modelPackages maps region to the SageMaker model registry details
+	// of its model packages and groups
+	modelPackages map[string]*modelRegistry
+	// subscriptions maps region to SNS topic ARNs to how many
+	// subscriptions they have
+	subscriptions map[string]map[string]int
+}
+
+// NewEnricher returns an Enricher making its calls with cfg
+func NewEnricher(cfg aws.Config) *Enricher {
+	return &Enricher{cfg: cfg, rdsEngines: map[string]map[string]string{}, bucketRegions: map[string]string{}, modelPackages: map[string]*modelRegistry{}, subscriptions: map[string]map[string]int{}}
+}
+
+// Enrich adds what it can find out about r. It's meant to be called with
+// the raw ARN service code still in Service, before friendly names are
+// applied.
+func (e *Enricher) Enrich(ctx context.Context, r *SingleResource) {
+	switch DerefNilPointerStrings(r.Service) {
+	case "rds":
+		e.enrichRDS(ctx, r)
+	case "s3":
+		e.enrichS3(ctx, r)
+	case "sagemaker":
+		e.enrichSageMaker(ctx, r)
+	case "sns":
+		e.enrichSNS(ctx, r)
+	}
+}
+
+// enrichS3 sets the Region of S3 buckets to the one they're actually in.
+// Bucket ARNs have no region, so otherwise they get the region they were
+// listed from.
+func (e *Enricher) enrichS3(ctx context.Context, r *SingleResource) {
+	// Only buckets are plain names, access points and the like are
+	// type/name and already have their region in the ARN
+	if r.ID == nil || strings.Contains(*r.ID, "/") {
+		return
+	}
+	bucket := *r.ID
+	region, ok := e.bucketRegions[bucket]
+	if !ok {
+		var err error
+		if region, err = bucketRegion(ctx, e.cfg, DerefNilPointerStrings(r.Region), bucket); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: --enrich can't get the location of bucket %s: %v\n", bucket, err)
+		}
+		e.bucketRegions[bucket] = region
+	}
+	if region != "" {
+		r.Region = &region
+	}
+}
+
+// bucketRegion asks S3 which region the bucket is in
+func bucketRegion(ctx context.Context, cfg aws.Config, region, bucket string) (string, error) {
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.Region = region
+	})
+	out, err := client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: &bucket})
+	if err != nil {
+		return "", err
+	}
+	// For historical reasons us-east-1 is an empty location and the
+	// oldest eu-west-1 buckets say EU
+	switch loc := string(out.LocationConstraint); loc {
+	case "":
+		return "us-east-1", nil
+	case "EU":
+		return "eu-west-1", nil
+	default:
+		return loc, nil
+	}
+}
+
+// enrichRDS sets Details of DB instances and clusters to their engine.
+// Neptune and DocumentDB share the rds ARNs with the RDS engines, the
+// engine (neptune, docdb, aurora-postgresql, ...) tells them apart.
+func (e *Enricher) enrichRDS(ctx context.Context, r *SingleResource) {
+	product := DerefNilPointerStrings(r.Product)
+	if product != "db" && product != "cluster" {
+		return
+	}
+	region := DerefNilPointerStrings(r.Region)
+	engines, ok := e.rdsEngines[region]
+	if !ok {
+		var err error
+		if engines, err = describeRDSEngines(ctx, e.cfg, region); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: --enrich can't describe RDS in %s: %v\n", region, err)
+		}
+		e.rdsEngines[region] = engines
+	}
+	if engine, ok := engines[DerefNilPointerStrings(r.ARN)]; ok {
+		r.Details = &engine
+	}
+}
+
+// describeRDSEngines lists the DB instances and clusters of the region,
+// returning a map of their ARNs to their engine
+func describeRDSEngines(ctx context.Context, cfg aws.Config, region string) (map[string]string, error) {
+	client := rds.NewFromConfig(cfg, func(o *rds.Options) {
+		o.Region = region
+	})
+	engines := map[string]string{}
+
+	instances := rds.NewDescribeDBInstancesPaginator(client, &rds.DescribeDBInstancesInput{})
+	for instances.HasMorePages() {
+		out, err := instances.NextPage(ctx)
+		if err != nil {
+			return engines, err
+		}
+		for _, db := range out.DBInstances {
+			engines[aws.ToString(db.DBInstanceArn)] = aws.ToString(db.Engine)
+		}
+	}
+
+	clusters := rds.NewDescribeDBClustersPaginator(client, &rds.DescribeDBClustersInput{})
+	for clusters.HasMorePages() {
+		out, err := clusters.NextPage(ctx)
+		if err != nil {
+			return engines, err
+		}
+		for _, c := range out.DBClusters {
+			engines[aws.ToString(c.DBClusterArn)] = aws.ToString(c.Engine)
+		}
+	}
+	return engines, nil
+}
+
+// modelRegistry is what enrichSageMaker knows about the model registry of
+// a region. SageMaker lowercases the names in its ARNs, so both maps are
+// keyed by lowercased ARN.
+type modelRegistry struct {
+	// approval maps model package versions to their approval status
+	approval map[string]string
+	// latestApproved maps model package groups to their newest approved
+	// version
+	latestApproved map[string]int32
+}
+
+// enrichSageMaker sets Details of model package versions to their approval
+// status and of model package groups to their latest approved version,
+// for ML governance. A group without an approved version is left as is.
+func (e *Enricher) enrichSageMaker(ctx context.Context, r *SingleResource) {
+	product := DerefNilPointerStrings(r.Product)
+	if product != "model-package" && product != "model-package-group" {
+		return
+	}
+	region := DerefNilPointerStrings(r.Region)
+	registry, ok := e.modelPackages[region]
+	if !ok {
+		var err error
+		if registry, err = listModelRegistry(ctx, e.cfg, region); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: --enrich can't list SageMaker model packages in %s: %v\n", region, err)
+		}
+		e.modelPackages[region] = registry
+	}
+
+	arn := strings.ToLower(DerefNilPointerStrings(r.ARN))
+	var details string
+	if product == "model-package" {
+		details = registry.approval[arn]
+	} else if v, ok := registry.latestApproved[arn]; ok {
+		details = fmt.Sprintf("latest approved: v%d", v)
+	}
+	if details != "" {
+		r.Details = &details
+	}
+}
+
+// listModelRegistry lists the versioned model packages of the region,
+// one paginated call covering every group
+func listModelRegistry(ctx context.Context, cfg aws.Config, region string) (*modelRegistry, error) {
+	client := sagemaker.NewFromConfig(cfg, func(o *sagemaker.Options) {
+		o.Region = region
+	})
+	registry := &modelRegistry{approval: map[string]string{}, latestApproved: map[string]int32{}}
+
+	packages := sagemaker.NewListModelPackagesPaginator(client, &sagemaker.ListModelPackagesInput{
+		ModelPackageType: sagemakertypes.ModelPackageTypeVersioned,
+	})
+	for packages.HasMorePages() {
+		out, err := packages.NextPage(ctx)
+		if err != nil {
+			return registry, err
+		}
+		for _, p := range out.ModelPackageSummaryList {
+			arn := strings.ToLower(aws.ToString(p.ModelPackageArn))
+			registry.approval[arn] = string(p.ModelApprovalStatus)
+			if p.ModelApprovalStatus != sagemakertypes.ModelApprovalStatusApproved || p.ModelPackageVersion == nil {
+				continue
+			}
+			// The group's ARN is the version's with model-package/group/N
+			// turned into model-package-group/group
+			i := strings.LastIndex(arn, "/")
+			if i < 0 {
+				continue
+			}
+			group := strings.Replace(arn[:i], ":model-package/", ":model-package-group/", 1)
+			if *p.ModelPackageVersion > registry.latestApproved[group] {
+				registry.latestApproved[group] = *p.ModelPackageVersion
+			}
+		}
+	}
+	return registry, nil
+}
+
+// enrichSNS sets Details of SNS topics to how many subscriptions they
+// have, so dead topics without any stand out as "0 subscriptions"
+func (e *Enricher) enrichSNS(ctx context.Context, r *SingleResource) {
+	// Subscriptions are topic ARNs with the subscription id in Details
+	if DerefNilPointerStrings(r.Product) != "topic" || DerefNilPointerStrings(r.Details) != "" {
+		return
+	}
+	region := DerefNilPointerStrings(r.Region)
+	counts, ok := e.subscriptions[region]
+	if !ok {
+		var err error
+		if counts, err = countSubscriptions(ctx, e.cfg, region); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: --enrich can't list SNS subscriptions in %s: %v\n", region, err)
+			// Counting a topic as unsubscribed when the listing failed
+			// would send it to the cleanup list by mistake
+			counts = nil
+		}
+		e.subscriptions[region] = counts
+	}
+	if counts == nil {
+		return
+	}
+	n := counts[DerefNilPointerStrings(r.ARN)]
+	details := fmt.Sprintf("%d subscriptions", n)
+	if n == 1 {
+		details = "1 subscription"
+	}
+	r.Details = &details
+}
+
+// countSubscriptions counts the subscriptions of every topic in the
+// region. A single ListSubscriptions listing covers all of them, rather
+// than a ListSubscriptionsByTopic call per topic.
+func countSubscriptions(ctx context.Context, cfg aws.Config, region string) (map[string]int, error) {
+	client := sns.NewFromConfig(cfg, func(o *sns.Options) {
+		o.Region = region
+	})
+	counts := map[string]int{}
+
+	subscriptions := sns.NewListSubscriptionsPaginator(client, &sns.ListSubscriptionsInput{})
+	for subscriptions.HasMorePages() {
+		out, err := subscriptions.NextPage(ctx)
+		if err != nil {
+			return counts, err
+		}
+		for _, sub := range out.Subscriptions {
+			counts[aws.ToString(sub.TopicArn)]++
+		}
+	}
+	return counts, nil
+}
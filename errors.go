@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/smithy-go"
+)
+
+// RegionError records why scanning a region failed
+type RegionError struct {
+	Region  string `json:"region"`
+	Message string `json:"error"`
+	// BlockedBySCP is set when an organization's service control policy
+	// denied the request, rather than a missing IAM permission
+	BlockedBySCP bool `json:"blockedBySCP,omitempty"`
+}
+
+// NewRegionError builds a RegionError, boiling AWS API errors down to
+// their code and message rather than the whole operation error chain
+func NewRegionError(region string, err error) RegionError {
+	msg := err.Error()
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		msg = apiErr.ErrorCode() + ": " + apiErr.ErrorMessage()
+	}
+	return RegionError{Region: region, Message: msg, BlockedBySCP: isSCPDenial(msg)}
+}
+
+// isSCPDenial reports whether an error message is an explicit deny from a
+// service control policy, which is how Control Tower blocks regions. AWS
+// says so in the message, e.g. "... with an explicit deny in a service
+// control policy".
+func isSCPDenial(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "service control policy") ||
+		(strings.Contains(msg, "explicit deny") && strings.Contains(msg, "organizations"))
+}
+
+// ScanErrors collects the failures of every region we scanned so they
+// can be reported together once the results have been rendered
+type ScanErrors []RegionError
+
+func (e ScanErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, re := range e {
+		parts[i] = re.Region + ": " + re.Message
+		if re.BlockedBySCP {
+			parts[i] = re.Region + ": blocked by SCP (" + re.Message + ")"
+		}
+	}
+	return "Errors: " + strings.Join(parts, "; ")
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+)
+
+// GetResourcesAPI is the one call of the tagging API client that
+// FetchResources needs, so a scan can be fed canned pages
+type GetResourcesAPI interface {
+	GetResources(ctx context.Context, params *resourcegroupstaggingapi.GetResourcesInput, optFns ...func(*resourcegroupstaggingapi.Options)) (*resourcegroupstaggingapi.GetResourcesOutput, error)
+}
+
+// ScanOptions tunes how FetchResources pages through a region
+type ScanOptions struct {
+	// ResourceTypes limits the scan to these resource types, e.g.
+	// "ec2:instance" or "s3"
+	ResourceTypes []string
+	// TagKeys limits the scan to resources that have all of these tag
+	// keys, whatever their value
+	TagKeys []string
+	// Breaker, when set, retries throttled pages and keeps the scan
+	// within its retry budget
+	Breaker *ThrottleBreaker
+	// PageHook, when set, is called after every page with the page number
+	// (starting at 1) and how many resources were fetched so far. Returning
+	// false stops the pagination early without an error.
+	PageHook func(pageNum int, fetched int) bool
+	// PageDelay is how long to wait between pages, to go easy on
+	// accounts with strict API limits
+	PageDelay time.Duration
+	// EmptyPageRetries is how many times a page that came back empty but
+	// with a pagination token is asked for again before moving on. Either
+	// way the scan carries on for as long as there's a token.
+	EmptyPageRetries int
+	// RetryHook, when set, is called before every retry of a page, whether
+	// it was throttled or came back empty
+	RetryHook func(region string, pageNum, attempt int, err error, delay time.Duration)
+	// StartToken is the pagination token to start from instead of the
+	// first page, to resume an interrupted scan
+	StartToken string
+	// CheckpointHook, when set, is called once every resource of a page
+	// has been handed over, with the token of the next page ("" after the
+	// last one). An error stops the scan.
+	CheckpointHook func(token string) error
+}
+
+// emptyPageBackoff is how long to wait before asking for an empty page
+// again, growing with every retry
+const emptyPageBackoff = 500 * time.Millisecond
+
+// errEmptyPage is what RetryHook is given for empty pages that are retried
+var errEmptyPage = errors.New("empty page with a pagination token")
+
+// ListResources lists every resource of a region and returns them all at
+// once. It's the simplest way to embed the scan, use FetchResources to
+// process resources as they're fetched instead.
+func ListResources(ctx context.Context, cfg aws.Config, region string, opts ScanOptions) ([]*SingleResource, error) {
+	r := resourcegroupstaggingapi.NewFromConfig(cfg, func(o *resourcegroupstaggingapi.Options) {
+		o.Region = region
+		if opts.Breaker != nil {
+			o.Retryer = NoThrottleRetryer()
+		}
+	})
+
+	var resources []*SingleResource
+	err := FetchResources(ctx, r, region, opts, func(res *SingleResource) error {
+		resources = append(resources, res)
+		return nil
+	})
+	return resources, err
+}
+
+// FetchResources pages through the tagging API for the given region and
+// hands every converted resource over to fn as soon as it's parsed, so
+// the caller decides whether to stream it straight out or buffer it.
+func FetchResources(ctx context.Context, r GetResourcesAPI, region string, opts ScanOptions, fn func(*SingleResource) error) error {
+	// The results will come paginated, so we keep the token outside
+	// the loop and keep updating it until there are no more results.
+	paginationToken := opts.StartToken
+	var pageNum, fetched, emptyRetries int
+
+	for {
+		in := &resourcegroupstaggingapi.GetResourcesInput{
+			ResourcesPerPage:    aws.Int32(50),
+			ResourceTypeFilters: opts.ResourceTypes,
+		}
+		for _, k := range opts.TagKeys {
+			in.TagFilters = append(in.TagFilters, types.TagFilter{Key: aws.String(k)})
+		}
+		if paginationToken != "" {
+			in.PaginationToken = &paginationToken
+		}
+
+		var onRetry func(int, error, time.Duration)
+		if opts.RetryHook != nil {
+			page := pageNum + 1
+			onRetry = func(attempt int, err error, delay time.Duration) {
+				opts.RetryHook(region, page, attempt, err, delay)
+			}
+		}
+
+		var out *resourcegroupstaggingapi.GetResourcesOutput
+		err := opts.Breaker.Do(ctx, func() (err error) {
+			out, err = r.GetResources(ctx, in)
+			return err
+		}, onRetry)
+		if err != nil {
+			return err
+		}
+
+		// An empty page that still has a token after it is usually the
+		// API being eventually consistent, so give it a moment and ask
+		// for the same page again
+		if len(out.ResourceTagMappingList) == 0 && aws.ToString(out.PaginationToken) != "" && emptyRetries < opts.EmptyPageRetries {
+			emptyRetries++
+			debugf("%s: page %d came back empty, retrying (%d/%d)", region, pageNum+1, emptyRetries, opts.EmptyPageRetries)
+			delay := time.Duration(emptyRetries) * emptyPageBackoff
+			if opts.RetryHook != nil {
+				opts.RetryHook(region, pageNum+1, emptyRetries, errEmptyPage, delay)
+			}
+			if err := sleep(ctx, delay); err != nil {
+				return err
+			}
+			continue
+		}
+		emptyRetries = 0
+
+		for _, resource := range out.ResourceTagMappingList {
+			// Shouldn't happen, but one broken entry isn't worth
+			// crashing the whole scan over
+			if aws.ToString(resource.ResourceARN) == "" {
+				debugf("%s: skipping a resource without an ARN on page %d", region, pageNum+1)
+				continue
+			}
+
+			svc := ServiceNameFromARN(resource.ResourceARN)
+			rgn := region
+
+			res := ConvertArnToSingleResource(resource.ResourceARN, svc, &rgn)
+			res.Tags = TagsFromMapping(resource.Tags)
+
+			if err := fn(res); err != nil {
+				return err
+			}
+		}
+
+		pageNum++
+		fetched += len(out.ResourceTagMappingList)
+		if opts.PageHook != nil && !opts.PageHook(pageNum, fetched) {
+			return nil
+		}
+
+		paginationToken = aws.ToString(out.PaginationToken)
+		if opts.CheckpointHook != nil {
+			if err := opts.CheckpointHook(paginationToken); err != nil {
+				return err
+			}
+		}
+		if paginationToken == "" {
+			return nil
+		}
+		if opts.PageDelay > 0 {
+			if err := sleep(ctx, opts.PageDelay); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// FetchResourcesConcurrently splits the scan of a region into one paginated
+// scan per resource type and runs up to concurrency of them at the same
+// time. Pagination tokens are serial, so for one huge region this is the
+// only way to get pages in parallel. Calls to fn are serialised, and the
+// first error cancels the remaining scans.
+func FetchResourcesConcurrently(ctx context.Context, r GetResourcesAPI, region string, opts ScanOptions, concurrency int, fn func(*SingleResource) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+
+	safeFn := func(res *SingleResource) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return fn(res)
+	}
+
+	for _, t := range opts.ResourceTypes {
+		wg.Add(1)
+		go func(t string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			typeOpts := opts
+			typeOpts.ResourceTypes = []string{t}
+
+			if err := FetchResources(ctx, r, region, typeOpts, safeFn); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				cancel()
+			}
+		}(t)
+	}
+
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+)
+
+// fakeTaggingAPI hands out canned pages, keyed by the token that leads to
+// them, the first one under an empty token
+type fakeTaggingAPI struct {
+	pages map[string]*resourcegroupstaggingapi.GetResourcesOutput
+	// flaky pages are handed out instead the first time their token is
+	// asked for, like an eventually consistent API would
+	flaky map[string]*resourcegroupstaggingapi.GetResourcesOutput
+	// calls counts how many times each token was asked for
+	calls map[string]int
+}
+
+func (f *fakeTaggingAPI) GetResources(ctx context.Context, in *resourcegroupstaggingapi.GetResourcesInput, optFns ...func(*resourcegroupstaggingapi.Options)) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+	token := aws.ToString(in.PaginationToken)
+	if f.calls == nil {
+		f.calls = map[string]int{}
+	}
+	f.calls[token]++
+	if out, ok := f.flaky[token]; ok && f.calls[token] == 1 {
+		return out, nil
+	}
+	return f.pages[token], nil
+}
+
+// page builds a page of resources with the given ARNs, a nil ARN standing
+// for an entry without one, and the token of the next page
+func page(next string, arns ...*string) *resourcegroupstaggingapi.GetResourcesOutput {
+	out := &resourcegroupstaggingapi.GetResourcesOutput{}
+	if next != "" {
+		out.PaginationToken = aws.String(next)
+	}
+	for _, arn := range arns {
+		out.ResourceTagMappingList = append(out.ResourceTagMappingList, types.ResourceTagMapping{ResourceARN: arn})
+	}
+	return out
+}
+
+// fetchARNs runs FetchResources against api and returns the ARNs of the
+// resources it handed over
+func fetchARNs(t *testing.T, api GetResourcesAPI, opts ScanOptions) []string {
+	t.Helper()
+	var arns []string
+	err := FetchResources(context.Background(), api, testRegion, opts, func(r *SingleResource) error {
+		arns = append(arns, DerefNilPointerStrings(r.ARN))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("FetchResources: %v", err)
+	}
+	return arns
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFetchResourcesSkipsEntriesWithoutARN(t *testing.T) {
+	api := &fakeTaggingAPI{pages: map[string]*resourcegroupstaggingapi.GetResourcesOutput{
+		"": page("page-2",
+			aws.String("arn:aws:sqs:us-east-1:123456789012:first"),
+			nil,
+			aws.String(""),
+			aws.String("arn:aws:sqs:us-east-1:123456789012:second"),
+		),
+		"page-2": page("", aws.String("arn:aws:sqs:us-east-1:123456789012:third")),
+	}}
+
+	got := fetchARNs(t, api, ScanOptions{})
+	want := []string{
+		"arn:aws:sqs:us-east-1:123456789012:first",
+		"arn:aws:sqs:us-east-1:123456789012:second",
+		"arn:aws:sqs:us-east-1:123456789012:third",
+	}
+	if !equalStrings(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestFetchResourcesFollowsTokenPastEmptyPage(t *testing.T) {
+	api := &fakeTaggingAPI{pages: map[string]*resourcegroupstaggingapi.GetResourcesOutput{
+		"":       page("page-2"),
+		"page-2": page("page-3"),
+		"page-3": page("", aws.String("arn:aws:sqs:us-east-1:123456789012:third")),
+	}}
+
+	got := fetchARNs(t, api, ScanOptions{})
+	want := []string{"arn:aws:sqs:us-east-1:123456789012:third"}
+	if !equalStrings(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if api.calls[""] != 1 || api.calls["page-2"] != 1 {
+		t.Errorf("empty pages were asked for again without EmptyPageRetries: %v", api.calls)
+	}
+}
+
+func TestFetchResourcesRetriesEmptyPage(t *testing.T) {
+	api := &fakeTaggingAPI{
+		flaky: map[string]*resourcegroupstaggingapi.GetResourcesOutput{
+			"": page("page-2"),
+		},
+		pages: map[string]*resourcegroupstaggingapi.GetResourcesOutput{
+			"":       page("page-2", aws.String("arn:aws:sqs:us-east-1:123456789012:first")),
+			"page-2": page("", aws.String("arn:aws:sqs:us-east-1:123456789012:second")),
+		},
+	}
+
+	var retries []error
+	got := fetchARNs(t, api, ScanOptions{
+		EmptyPageRetries: 2,
+		RetryHook: func(region string, pageNum, attempt int, err error, delay time.Duration) {
+			retries = append(retries, err)
+		},
+	})
+	want := []string{
+		"arn:aws:sqs:us-east-1:123456789012:first",
+		"arn:aws:sqs:us-east-1:123456789012:second",
+	}
+	if !equalStrings(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if api.calls[""] != 2 {
+		t.Errorf("first page asked for %d times, want 2", api.calls[""])
+	}
+	if len(retries) != 1 || retries[0] != errEmptyPage {
+		t.Errorf("got retries %v, want a single errEmptyPage", retries)
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Filter is a parsed --filter expression, e.g.
+//
+//	service == "ec2" && tags.Environment == "prod" && region != "us-east-1"
+//
+// Comparisons are a field (any column, or tags.<key>) against a double
+// quoted string with ==, !=, =~ or !~, the last two taking a regular
+// expression. They combine with &&, || and !, and group with parentheses.
+// A missing tag compares as an empty string.
+type Filter interface {
+	Match(r *SingleResource) bool
+}
+
+// ParseFilter parses a --filter expression
+func ParseFilter(expr string) (Filter, error) {
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	f, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("--filter: unexpected %q", p.tokens[p.pos].text)
+	}
+	return f, nil
+}
+
+type filterTokenKind int
+
+const (
+	filterField filterTokenKind = iota
+	filterString
+	filterOp
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+}
+
+// filterOps are the operators, longest first so && isn't read as two &
+var filterOps = []string{"&&", "||", "==", "!=", "=~", "!~", "!", "(", ")"}
+
+// lexFilter splits the expression into fields, quoted strings and
+// operators
+func lexFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("--filter: unterminated string starting at %d", i)
+			}
+			s, err := strconv.Unquote(expr[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("--filter: bad string %s: %w", expr[i:end+1], err)
+			}
+			tokens = append(tokens, filterToken{filterString, s})
+			i = end + 1
+		case isFilterFieldChar(c):
+			end := i
+			for end < len(expr) && isFilterFieldChar(expr[end]) {
+				end++
+			}
+			tokens = append(tokens, filterToken{filterField, expr[i:end]})
+			i = end
+		default:
+			op := ""
+			for _, o := range filterOps {
+				if strings.HasPrefix(expr[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("--filter: unexpected %q at %d", c, i)
+			}
+			tokens = append(tokens, filterToken{filterOp, op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+func isFilterFieldChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.' || c == ':' || c == '/'
+}
+
+// filterParser is a recursive descent parser over the tokens, && binding
+// tighter than ||
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peekOp(op string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == filterOp && p.tokens[p.pos].text == op
+}
+
+func (p *filterParser) or() (Filter, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOp("||") {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = orFilter{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) and() (Filter, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOp("&&") {
+		p.pos++
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = andFilter{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) unary() (Filter, error) {
+	switch {
+	case p.peekOp("!"):
+		p.pos++
+		f, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notFilter{f}, nil
+	case p.peekOp("("):
+		p.pos++
+		f, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peekOp(")") {
+			return nil, fmt.Errorf("--filter: missing )")
+		}
+		p.pos++
+		return f, nil
+	}
+	return p.comparison()
+}
+
+func (p *filterParser) comparison() (Filter, error) {
+	if p.pos+3 > len(p.tokens) {
+		return nil, fmt.Errorf("--filter: expected a comparison like service == \"ec2\"")
+	}
+	field, op, value := p.tokens[p.pos], p.tokens[p.pos+1], p.tokens[p.pos+2]
+	if field.kind != filterField || op.kind != filterOp || value.kind != filterString {
+		return nil, fmt.Errorf("--filter: expected a comparison like service == \"ec2\", got %s %s %s", field.text, op.text, value.text)
+	}
+	p.pos += 3
+
+	name := field.text
+	if !strings.HasPrefix(name, tagFieldPrefix) {
+		name = strings.ToLower(name)
+		if _, ok := columnHeaders[name]; !ok {
+			return nil, fmt.Errorf("--filter: unknown field %q", field.text)
+		}
+	}
+
+	c := compareFilter{field: name, op: op.text, value: value.text}
+	switch op.text {
+	case "==", "!=":
+	case "=~", "!~":
+		re, err := regexp.Compile(value.text)
+		if err != nil {
+			return nil, fmt.Errorf("--filter: %w", err)
+		}
+		c.re = re
+	default:
+		return nil, fmt.Errorf("--filter: %s isn't a comparison operator", op.text)
+	}
+	return c, nil
+}
+
+type andFilter struct{ left, right Filter }
+
+func (f andFilter) Match(r *SingleResource) bool { return f.left.Match(r) && f.right.Match(r) }
+
+type orFilter struct{ left, right Filter }
+
+func (f orFilter) Match(r *SingleResource) bool { return f.left.Match(r) || f.right.Match(r) }
+
+type notFilter struct{ f Filter }
+
+func (f notFilter) Match(r *SingleResource) bool { return !f.f.Match(r) }
+
+type compareFilter struct {
+	field string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+func (f compareFilter) Match(r *SingleResource) bool {
+	// Tags are compared raw, whatever --humanize-tag shows
+	var v string
+	if strings.HasPrefix(f.field, tagFieldPrefix) {
+		v = r.Tags[strings.TrimPrefix(f.field, tagFieldPrefix)]
+	} else {
+		v = r.Column(f.field)
+	}
+
+	switch f.op {
+	case "==":
+		return v == f.value
+	case "!=":
+		return v != f.value
+	case "=~":
+		return f.re.MatchString(v)
+	default:
+		return !f.re.MatchString(v)
+	}
+}
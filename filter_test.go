@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterMatch(t *testing.T) {
+	res := testResource("arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0", "us-east-1", Tags{"Environment": "prod", "Team": "payments"})
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`service == "ec2"`, true},
+		{`service != "ec2"`, false},
+		{`SERVICE == "ec2"`, true},
+		{`tags.Environment == "prod"`, true},
+		{`tags.Environment == "Prod"`, false},
+		// && binds tighter than ||, so these are a || (b && c)
+		{`service == "ec2" || service == "s3" && region == "eu-west-1"`, true},
+		{`service == "s3" && region == "eu-west-1" || service == "ec2"`, true},
+		{`service == "s3" || service == "ec2" && region == "eu-west-1"`, false},
+		// Parentheses override that
+		{`(service == "ec2" || service == "s3") && region == "eu-west-1"`, false},
+		{`(service == "ec2" || service == "s3") && (region == "eu-west-1" || region == "us-east-1")`, true},
+		{`!service == "s3"`, true},
+		{`!(service == "ec2" && tags.Team == "payments")`, false},
+		{`!!service == "ec2"`, true},
+		{`id =~ "^i-0123"`, true},
+		{`id =~ "^I-0123"`, false},
+		{`id =~ "(?i)^I-0123"`, true},
+		{`arn !~ ":eu-"`, true},
+		{`arn !~ ":us-"`, false},
+		// A missing tag is an empty string
+		{`tags.Owner == ""`, true},
+		{`tags.Owner != ""`, false},
+		{`tags.Owner !~ "."`, true},
+		{`tags.Team == "payments" && tags.Owner == ""`, true},
+		{`tags.name == "a \"quoted\" name"`, false},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if got := f.Match(res); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestFilterSeesTransformedFields(t *testing.T) {
+	res := testResource("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/50dc6c495c0c9188", "us-east-1", nil)
+	ApplyFriendlyServiceName(res)
+	ApplyAccountName(res, map[string]string{"123456789012": "prod"})
+
+	for _, expr := range []string{`service == "elb"`, `account-name == "prod"`} {
+		f, err := ParseFilter(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !f.Match(res) {
+			t.Errorf("%s didn't match the friendly and account names", expr)
+		}
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`colour == "red"`, `unknown field "colour"`},
+		{`service == "ec2`, "unterminated string"},
+		{`tags.Note == "a \"b`, "unterminated string"},
+		{`(service == "ec2"`, "missing )"},
+		{`service == "ec2")`, `unexpected ")"`},
+		{`service == "ec2" &&`, "expected a comparison"},
+		{`service "ec2"`, "expected a comparison"},
+		{`service == ec2`, "expected a comparison"},
+		{`service && "ec2"`, "isn't a comparison operator"},
+		{`id =~ "("`, "missing closing )"},
+		{`service == "ec2" & region == "us-east-1"`, "unexpected '&'"},
+		{``, "expected a comparison"},
+	}
+	for _, tt := range tests {
+		_, err := ParseFilter(tt.expr)
+		if err == nil {
+			t.Errorf("%s: parsed", tt.expr)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %q, want it to mention %q", tt.expr, err, tt.want)
+		}
+	}
+}
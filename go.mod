@@ -5,6 +5,21 @@ go 1.16
 require (
 	github.com/aws/aws-sdk-go-v2 v1.8.1
 	github.com/aws/aws-sdk-go-v2/config v1.6.1
+	github.com/aws/aws-sdk-go-v2/service/ec2 v1.14.0
+	github.com/aws/aws-sdk-go-v2/service/organizations v1.5.2
+	github.com/aws/aws-sdk-go-v2/service/rds v1.7.0
 	github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi v1.4.3
+	github.com/aws/aws-sdk-go-v2/service/s3 v1.13.0
+	github.com/aws/aws-sdk-go-v2/service/sagemaker v1.11.0
+	github.com/aws/aws-sdk-go-v2/service/savingsplans v1.0.0
+	github.com/aws/aws-sdk-go-v2/service/sns v1.7.2
+	github.com/aws/aws-sdk-go-v2/service/sts v1.6.2
+	github.com/aws/smithy-go v1.7.0
+	github.com/charmbracelet/bubbletea v0.19.3
 	github.com/olekukonko/tablewriter v0.0.5
+	github.com/xuri/excelize/v2 v2.4.1
+	golang.org/x/term v0.0.0-20210422114643-f5beecf764ed
+	google.golang.org/grpc v1.40.0
+	google.golang.org/protobuf v1.25.0
+	modernc.org/sqlite v1.14.8
 )
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+)
+
+// NewHTTPClient builds the HTTP client used for every AWS call, starting
+// from the SDK's defaults. Proxies set through HTTPS_PROXY/NO_PROXY are
+// honoured, timeout limits each request as a whole (0 means no limit)
+// and maxIdleConns caps the idle connections kept around per host, which
+// matters once many regions are scanned at the same time.
+func NewHTTPClient(timeout time.Duration, maxIdleConns int) *awshttp.BuildableClient {
+	return awshttp.NewBuildableClient().
+		WithTimeout(timeout).
+		WithTransportOptions(func(t *http.Transport) {
+			t.Proxy = http.ProxyFromEnvironment
+			if maxIdleConns > 0 {
+				t.MaxIdleConns = maxIdleConns
+				t.MaxIdleConnsPerHost = maxIdleConns
+			}
+		})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ReadInput reads resources saved by an earlier scan for --input, from
+// path or from stdin when path is "-". It takes --output json documents,
+// plain JSON arrays of resources and --output jsonl, as long as they were
+// written without --field-map. Gzipped files, e.g. from --gzip, are
+// decompressed on the fly.
+func ReadInput(path string) ([]*SingleResource, error) {
+	r := io.Reader(os.Stdin)
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	br := bufio.NewReader(r)
+	in := io.Reader(br)
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("reading --input %s: %w", path, err)
+		}
+		defer gz.Close()
+		in = gz
+	}
+
+	resources, err := decodeResources(in)
+	if err != nil {
+		return nil, fmt.Errorf("reading --input %s: %w", path, err)
+	}
+	return resources, nil
+}
+
+// decodeResources decodes every top level JSON value in r. An array holds
+// resources, an object with "resources" is a RenderJSON document and any
+// other object is a single resource, one per line in jsonl.
+func decodeResources(r io.Reader) ([]*SingleResource, error) {
+	var resources []*SingleResource
+	dec := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			return resources, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(raw) > 0 && raw[0] == '[' {
+			var list []*SingleResource
+			if err := json.Unmarshal(raw, &list); err != nil {
+				return nil, err
+			}
+			resources = append(resources, list...)
+			continue
+		}
+
+		var doc struct {
+			SchemaVersion int               `json:"schemaVersion"`
+			Resources     []*SingleResource `json:"resources"`
+		}
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+		if doc.SchemaVersion > OutputSchemaVersion {
+			return nil, fmt.Errorf("written with schema version %d, this awslist only understands up to %d", doc.SchemaVersion, OutputSchemaVersion)
+		}
+		if doc.Resources != nil {
+			resources = append(resources, doc.Resources...)
+			continue
+		}
+
+		res := &SingleResource{}
+		if err := json.Unmarshal(raw, res); err != nil {
+			return nil, err
+		}
+		resources = append(resources, res)
+	}
+}
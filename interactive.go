@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// browser is the bubbletea model behind --interactive. Typing filters
+// the rows by id and service, left/right picks the column to sort by.
+type browser struct {
+	columns []string
+	rows    [][]string
+
+	// shown are the indexes of the rows matching the filter, in the
+	// current sort order
+	shown   []int
+	filter  string
+	sortCol int
+	reverse bool
+
+	cursor int
+	offset int
+	width  int
+	height int
+}
+
+// Browse shows the resources in a scrollable, filterable table until
+// the user quits
+func Browse(resources []*SingleResource, columns []string) error {
+	b := &browser{columns: columns, height: 24}
+	for _, r := range resources {
+		b.rows = append(b.rows, r.row(columns))
+	}
+	b.refresh()
+
+	return tea.NewProgram(b, tea.WithAltScreen()).Start()
+}
+
+func (b *browser) Init() tea.Cmd {
+	return nil
+}
+
+func (b *browser) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		b.width, b.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			return b, tea.Quit
+		case tea.KeyEsc:
+			// Esc clears the filter first and quits once it's empty
+			if b.filter == "" {
+				return b, tea.Quit
+			}
+			b.filter = ""
+			b.refresh()
+		case tea.KeyUp:
+			b.move(-1)
+		case tea.KeyDown:
+			b.move(1)
+		case tea.KeyPgUp:
+			b.move(-b.pageSize())
+		case tea.KeyPgDown:
+			b.move(b.pageSize())
+		case tea.KeyHome:
+			b.move(-len(b.shown))
+		case tea.KeyEnd:
+			b.move(len(b.shown))
+		case tea.KeyLeft:
+			b.sortCol = (b.sortCol + len(b.columns) - 1) % len(b.columns)
+			b.refresh()
+		case tea.KeyRight:
+			b.sortCol = (b.sortCol + 1) % len(b.columns)
+			b.refresh()
+		case tea.KeyCtrlR:
+			b.reverse = !b.reverse
+			b.refresh()
+		case tea.KeyBackspace:
+			if b.filter != "" {
+				r := []rune(b.filter)
+				b.filter = string(r[:len(r)-1])
+				b.refresh()
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			b.filter += string(msg.Runes)
+			b.refresh()
+		}
+	}
+	return b, nil
+}
+
+// refresh works out the matching rows again after the filter or the
+// sort order changed
+func (b *browser) refresh() {
+	filter := strings.ToLower(b.filter)
+	idCol, serviceCol := indexOf(b.columns, "id"), indexOf(b.columns, "service")
+
+	b.shown = b.shown[:0]
+	for i, row := range b.rows {
+		if filter == "" || matchesFilter(row, idCol, filter) || matchesFilter(row, serviceCol, filter) {
+			b.shown = append(b.shown, i)
+		}
+	}
+
+	sort.SliceStable(b.shown, func(i, j int) bool {
+		a, c := b.rows[b.shown[i]][b.sortCol], b.rows[b.shown[j]][b.sortCol]
+		if b.reverse {
+			return a > c
+		}
+		return a < c
+	})
+
+	b.cursor, b.offset = 0, 0
+}
+
+func matchesFilter(row []string, col int, filter string) bool {
+	return col >= 0 && strings.Contains(strings.ToLower(row[col]), filter)
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// pageSize is the number of rows that fit below the header and above
+// the status lines
+func (b *browser) pageSize() int {
+	if n := b.height - 4; n > 0 {
+		return n
+	}
+	return 1
+}
+
+func (b *browser) move(n int) {
+	b.cursor += n
+	if b.cursor >= len(b.shown) {
+		b.cursor = len(b.shown) - 1
+	}
+	if b.cursor < 0 {
+		b.cursor = 0
+	}
+
+	// Keep the cursor on screen
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+b.pageSize() {
+		b.offset = b.cursor - b.pageSize() + 1
+	}
+}
+
+func (b *browser) View() string {
+	headers := columnHeaderRow(b.columns)
+	widths := make([]int, len(b.columns))
+	for i, h := range headers {
+		widths[i] = utf8.RuneCountInString(h) + 2
+	}
+	for _, row := range b.rows {
+		for i, v := range row {
+			if n := utf8.RuneCountInString(v); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for i, h := range headers {
+		if i == b.sortCol {
+			if b.reverse {
+				h += " v"
+			} else {
+				h += " ^"
+			}
+		}
+		sb.WriteString(fmt.Sprintf("  %-*s", widths[i], h))
+	}
+	sb.WriteString("\n")
+
+	end := b.offset + b.pageSize()
+	if end > len(b.shown) {
+		end = len(b.shown)
+	}
+	for n := b.offset; n < end; n++ {
+		prefix := "  "
+		if n == b.cursor {
+			prefix = "> "
+		}
+		line := prefix
+		for i, v := range b.rows[b.shown[n]] {
+			if i > 0 {
+				line += "  "
+			}
+			line += fmt.Sprintf("%-*s", widths[i], v)
+		}
+		sb.WriteString(truncate(line, b.width) + "\n")
+	}
+	for n := end - b.offset; n < b.pageSize(); n++ {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("\n%d/%d resources  filter: %s_\n", len(b.shown), len(b.rows), b.filter))
+	sb.WriteString(truncate("type to filter by id or service, left/right: sort column, ctrl+r: reverse, esc: clear/quit", b.width))
+	return sb.String()
+}
+
+// truncate cuts lines wider than the terminal so they don't wrap. It
+// counts runes rather than bytes, so it never cuts a character in half.
+func truncate(s string, width int) string {
+	if width > 0 && utf8.RuneCountInString(s) > width {
+		return string([]rune(s)[:width])
+	}
+	return s
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newTestBrowser(height int) *browser {
+	b := &browser{
+		columns: []string{"region", "service", "product", "id"},
+		rows: [][]string{
+			{"us-east-1", "ec2", "instance", "i-0b"},
+			{"eu-west-1", "s3", "", "logs"},
+			{"us-east-1", "ec2", "volume", "vol-0a"},
+			{"ap-south-1", "lambda", "function", "ec2-cleanup"},
+			{"us-west-2", "sqs", "queue", "jobs"},
+		},
+		height: height,
+	}
+	b.refresh()
+	return b
+}
+
+// shownIDs returns the ids of the rows shown, in order
+func (b *browser) shownIDs() []string {
+	var ids []string
+	for _, i := range b.shown {
+		ids = append(ids, b.rows[i][3])
+	}
+	return ids
+}
+
+func press(b *browser, keys ...tea.KeyMsg) tea.Cmd {
+	var cmd tea.Cmd
+	for _, k := range keys {
+		_, cmd = b.Update(k)
+	}
+	return cmd
+}
+
+func typed(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestBrowserFilterAndSort(t *testing.T) {
+	b := newTestBrowser(24)
+	if got, want := b.shownIDs(), []string{"ec2-cleanup", "logs", "i-0b", "vol-0a", "jobs"}; !equalStrings(got, want) {
+		t.Errorf("sorted by region: got %v, want %v", got, want)
+	}
+
+	// The filter matches ids as well as services, case insensitively
+	press(b, typed("E"), typed("c2"))
+	if got, want := b.shownIDs(), []string{"ec2-cleanup", "i-0b", "vol-0a"}; !equalStrings(got, want) {
+		t.Errorf("filtered by ec2: got %v, want %v", got, want)
+	}
+
+	press(b, tea.KeyMsg{Type: tea.KeyLeft})
+	if b.sortCol != 3 {
+		t.Errorf("left from the first column went to column %d, want the last one", b.sortCol)
+	}
+	if got, want := b.shownIDs(), []string{"ec2-cleanup", "i-0b", "vol-0a"}; !equalStrings(got, want) {
+		t.Errorf("sorted by id: got %v, want %v", got, want)
+	}
+	press(b, tea.KeyMsg{Type: tea.KeyCtrlR})
+	if got, want := b.shownIDs(), []string{"vol-0a", "i-0b", "ec2-cleanup"}; !equalStrings(got, want) {
+		t.Errorf("reversed: got %v, want %v", got, want)
+	}
+	press(b, tea.KeyMsg{Type: tea.KeyRight}, tea.KeyMsg{Type: tea.KeyRight})
+	if b.sortCol != 1 {
+		t.Errorf("right twice from the last column went to column %d, want 1", b.sortCol)
+	}
+
+	press(b, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace})
+	if b.filter != "E" || len(b.shown) != 3 {
+		t.Errorf("after backspaces got filter %q showing %d rows, want E showing 3", b.filter, len(b.shown))
+	}
+
+	// Esc clears the filter first, and only quits once there's none
+	if cmd := press(b, tea.KeyMsg{Type: tea.KeyEsc}); cmd != nil || b.filter != "" {
+		t.Errorf("esc with a filter: got filter %q and a command, want it cleared", b.filter)
+	}
+	cmd := press(b, tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("esc without a filter didn't quit")
+	}
+	if cmd() != tea.Quit() {
+		t.Error("esc without a filter didn't quit")
+	}
+}
+
+func TestBrowserMove(t *testing.T) {
+	// Two rows fit on a 6 line terminal
+	b := newTestBrowser(6)
+	tests := []struct {
+		key            tea.KeyType
+		cursor, offset int
+	}{
+		{tea.KeyUp, 0, 0},
+		{tea.KeyDown, 1, 0},
+		{tea.KeyDown, 2, 1},
+		{tea.KeyPgDown, 4, 3},
+		{tea.KeyDown, 4, 3},
+		{tea.KeyUp, 3, 3},
+		{tea.KeyUp, 2, 2},
+		{tea.KeyEnd, 4, 3},
+		{tea.KeyHome, 0, 0},
+		{tea.KeyPgDown, 2, 1},
+		{tea.KeyPgUp, 0, 0},
+	}
+	for i, tt := range tests {
+		press(b, tea.KeyMsg{Type: tt.key})
+		if b.cursor != tt.cursor || b.offset != tt.offset {
+			t.Errorf("step %d (%v): got cursor %d offset %d, want %d and %d", i, tt.key, b.cursor, b.offset, tt.cursor, tt.offset)
+		}
+	}
+
+	// A new filter starts from the top again
+	press(b, tea.KeyMsg{Type: tea.KeyEnd}, typed("s"))
+	if b.cursor != 0 || b.offset != 0 {
+		t.Errorf("after filtering got cursor %d offset %d, want 0 and 0", b.cursor, b.offset)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"us-east-1 ec2", 0, "us-east-1 ec2"},
+		{"us-east-1 ec2", 20, "us-east-1 ec2"},
+		{"us-east-1 ec2", 9, "us-east-1"},
+		{"bücket-ñame", 3, "büc"},
+		{"日本語のバケット", 4, "日本語の"},
+	}
+	for _, tt := range tests {
+		got := truncate(tt.s, tt.width)
+		if got != tt.want || !utf8.ValidString(got) {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
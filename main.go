@@ -1,173 +1,1153 @@
 package main
 
 import (
+	"compress/gzip"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
-	"github.com/olekukonko/tablewriter"
 )
 
+// typesCommand is set when awslist is run as "awslist types", which lists
+// the resource types found instead of the resources
+var typesCommand bool
+
+// tagKeysCommand is set when awslist is run as "awslist tag-keys", which
+// lists the tag keys in use instead of the resources
+var tagKeysCommand bool
+
+// tagValuesCommand is set when awslist is run as "awslist tag-values",
+// which lists the values of the --key tag instead of the resources
+var tagValuesCommand bool
+
+// globalRegion is the Region given to resources of global services
+// regardless of the region they were listed from
+const globalRegion = "global"
+
 // SingleResource defines how we want to describe each AWS resource
 type SingleResource struct {
-	Region  *string
-	Service *string
-	Product *string
-	Details *string
-	ID      *string
-	ARN     *string
+	// Partition is aws, aws-cn or aws-us-gov
+	Partition *string `json:"partition,omitempty" xml:"partition,omitempty"`
+	Region    *string `json:"region,omitempty" xml:"region,omitempty"`
+	Account   *string `json:"account,omitempty" xml:"account,omitempty"`
+	// AccountName is only set when account names are resolved through
+	// Organizations
+	AccountName *string `json:"accountName,omitempty" xml:"accountName,omitempty"`
+	Service     *string `json:"service,omitempty" xml:"service,omitempty"`
+	// ServiceCode keeps the raw ARN service code when Service has been
+	// swapped for a friendly name
+	ServiceCode *string `json:"serviceCode,omitempty" xml:"serviceCode,omitempty"`
+	Product     *string `json:"product,omitempty" xml:"product,omitempty"`
+	Details     *string `json:"details,omitempty" xml:"details,omitempty"`
+	ID          *string `json:"id,omitempty" xml:"id,omitempty"`
+	ARN         *string `json:"arn,omitempty" xml:"arn,omitempty"`
+	Tags        Tags    `json:"tags,omitempty" xml:"tags,omitempty"`
+	// MonthlyCost is a rough estimate in USD, only set with --estimate-cost
+	// for the resources it can price
+	MonthlyCost *string `json:"estimatedMonthlyCost,omitempty" xml:"estimatedMonthlyCost,omitempty"`
 }
 
-func PrettyPrintResources(resources []*SingleResource) {
-	var data [][]string
+// DerefNilPointerStrings utility func to make sure we don't run into
+// a "nil pointer dereference" issue during runtime.
+func DerefNilPointerStrings(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
 
-	for _, r := range resources {
-		row := []string{
-			DerefNilPointerStrings(r.Region),
-			DerefNilPointerStrings(r.Service),
-			DerefNilPointerStrings(r.Product),
-			DerefNilPointerStrings(r.ID),
+var (
+	regionFlag           = flag.String("region", "", "comma separated AWS regions to list resources from (can also be passed as the first argument)")
+	regionOrderFlag      = flag.String("region-order", "", "order resources by region, alphabetical or a comma separated list of regions to put first, e.g. us-east-1,eu-west-1")
+	regionsFileFlag      = flag.String("regions-file", "", "file with one AWS region per line, # starts a comment")
+	skipRegionCheckFlag  = flag.Bool("skip-region-check", false, "don't check regions against the list of known AWS regions, e.g. for newly launched ones")
+	outputFlag           = flag.String("output", "table", "output format: table, line, logfmt, arns, csv, json, jsonl (or ndjson), csv-per-service (with --output-dir), xml, html, xlsx, sqlite, iam-resources, dot, hash, tree, tf-import-blocks, tags-csv or influx (with --summary)")
+	selectFlag           = flag.String("select", "", "comma separated field paths for table, line, logfmt, csv, html and xlsx output, tags.<key> selects a tag, e.g. service,id,tags.CostCenter")
+	humanizeTagFlag      = flag.String("humanize-tag", "", "comma separated tag keys holding byte counts, shown as sizes (1073741824 as 1 GiB) in their tags.<key> columns")
+	jsonPrettyFlag       = flag.Bool("json-pretty", false, "indent --output json to make it easier to read, it's compact by default")
+	jsonFlattenFlag      = flag.Bool("json-flatten", false, "in json and jsonl output, put every tag in a top level tag_<key> field instead of a nested tags object")
+	templateFlag         = flag.String("template", "", "Go template executed for every resource instead of --output, e.g. '{{.Region}} {{.ID}} {{index .Tags \"Owner\"}}'")
+	templateFileFlag     = flag.String("template-file", "", "like --template but reads the template from this file")
+	iamWildcardFlag      = flag.String("iam-wildcard", "", "with --output iam-resources, replace these ARN segments with *: account,region")
+	hashAllFlag          = flag.Bool("hash-all", false, "with --output hash, hash every field instead of just the ARNs")
+	htmlInteractiveFlag  = flag.Bool("html-interactive", false, "with --output html, add a search box and sortable columns to the page")
+	fieldMapFlag         = flag.String("field-map", "", "rename fields in json, jsonl and csv output, e.g. id=resource_id,arn=resource_arn")
+	csvBOMFlag           = flag.Bool("csv-bom", false, "start csv output with a UTF-8 byte order mark for Excel")
+	gzipFlag             = flag.Bool("gzip", false, "gzip the output, e.g. --output jsonl --gzip for compact snapshots (on by default for an --output-file ending in .gz)")
+	outputFileFlag       = flag.String("output-file", "", "write the output to this file instead of stdout")
+	outputDirFlag        = flag.String("output-dir", "", "write one file per region (e.g. us-east-1.json) into this directory instead of stdout")
+	columnsFlag          = flag.String("columns", "", "comma separated columns for table, line, logfmt, csv, html and xlsx output: partition,region,account,account-name,service,product,id,details,arn,monthly-cost")
+	showPartitionFlag    = flag.Bool("show-partition", false, "add a partition column (aws, aws-cn, aws-us-gov) to the default columns")
+	friendlyFlag         = flag.Bool("friendly-names", false, "show friendly service names (elb, step-functions, ...) instead of ARN service codes")
+	explainFlag          = flag.Bool("explain", false, "explain which resources the tagging API returns and how many were filtered out")
+	watchFlag            = flag.Duration("watch", 0, "clear the screen and re-scan on this interval, e.g. 30s")
+	serviceFlag          = flag.String("service", "", "comma separated ARN service codes to list, e.g. ec2,rds. vpc selects all VPC related EC2 resources")
+	filterFlag           = flag.String("filter", "", "only list resources matching this expression, e.g. 'service == \"ec2\" && tags.Environment == \"prod\" && region != \"us-east-1\"'")
+	hasTagFlag           = flag.String("has-tag", "", "comma separated tag keys, only list resources that have all of them")
+	missingTagFlag       = flag.String("missing-tag", "", "comma separated tag keys, only list resources lacking at least one of them")
+	tagFlag              = flag.String("tag", "", "comma separated key=value tag filters, only list resources matching them (a bare key matches any value), e.g. Environment=prod,Team=payments")
+	keyFlag              = flag.String("key", "", "with the tag-values command, the tag key to list the values of")
+	tagsFilterModeFlag   = flag.String("tags-filter-mode", "all", "how --tag filters combine: all of them must match, or any one of them")
+	onlyGlobalFlag       = flag.Bool("only-global", false, "only list resources whose ARN has no region (IAM, Route 53, CloudFront, S3 buckets, ...)")
+	approvedRegionsFlag  = flag.String("approved-regions", "", "comma separated regions, only list resources outside of them, e.g. with --assert-none to fail CI on resources in unapproved regions")
+	onlyRegionalFlag     = flag.Bool("only-regional", false, "only list resources whose ARN has a region")
+	resourceTypeFlag     = flag.String("resource-type", "", "comma separated resource types to list, e.g. ec2:instance,s3")
+	enrichFlag           = flag.Bool("enrich", false, "look up details the ARNs don't carry with extra describe calls, e.g. the engine of RDS, Neptune and DocumentDB databases")
+	commitmentsFlag      = flag.Bool("include-commitments", false, "also list EC2 and RDS Reserved Instances and Savings Plans, which the tagging API never returns as they're billing commitments rather than resources")
+	estimateCostFlag     = flag.Bool("estimate-cost", false, "add a rough estimated monthly USD cost of running EC2 instances and EBS volumes, at us-east-1 on-demand prices")
+	accountNamesFlag     = flag.Bool("resolve-account-names", false, "look up account names through Organizations (needs org permissions) and add an account-name column")
+	summaryFlag          = flag.Bool("summary", false, "print resource counts per service instead of the resources")
+	summaryByTagFlag     = flag.String("summary-by-tag", "", "like --summary but counts resources per value of this tag, e.g. CostCenter")
+	byCategoryFlag       = flag.Bool("by-category", false, "like --summary but counts resources per service category: Compute, Storage, Networking, Database, Security, ...")
+	tagCoverageFlag      = flag.String("tag-coverage", "", "like --summary but reports per service the percentage of resources that have all these comma separated tags, e.g. CostCenter,Project")
+	categoryMapFlag      = flag.String("category-map", "", "with --by-category, comma separated service=Category overrides of the default categories, e.g. ecs=Containers,sagemaker=AI")
+	minResourcesFlag     = flag.Int("min-resources", 0, "with --summary, only show services with at least this many resources")
+	chartFlag            = flag.Bool("chart", false, "with --summary, draw the counts as a bar chart")
+	sortByCountFlag      = flag.String("sort-by-count", "desc", "with --summary, order services by resource count: asc or desc")
+	httpTimeoutFlag      = flag.Duration("http-timeout", 0, "timeout for each HTTP request to AWS, e.g. 30s (0 means no timeout)")
+	maxIdleConnsFlag     = flag.Int("max-idle-conns", 0, "maximum idle HTTP connections kept open per host (0 keeps the SDK default)")
+	retryBudgetFlag      = flag.Int("retry-budget", 100, "total number of throttled pages to retry across the whole scan before giving up with partial results")
+	breakerThresholdFlag = flag.Int("breaker-threshold", 5, "consecutive throttles after which the whole scan pauses for --cooloff")
+	cooloffFlag          = flag.Duration("cooloff", 30*time.Second, "how long to pause the scan once --breaker-threshold consecutive throttles are hit, and the longest a throttled page waits even when its Retry-After asks for more")
+	noPaginateFlag       = flag.Bool("no-paginate", false, "only fetch the first page of each region, for a quick check that everything works")
+	retryOnEmptyPageFlag = flag.Int("retry-on-empty-page", 0, "ask again up to this many times for a page that came back empty but has more pages after it")
+	resumeFlag           = flag.String("resume", "", "save how far the scan got to this file after every page, and carry on from there when it already exists")
+	retryLogFlag         = flag.String("retry-log", "", "append a JSON line to this file for every retried page, with its region, page, attempt, error and delay")
+	minPageDelayFlag     = flag.Duration("min-page-delay", 0, "wait this long between pages to keep the request rate down, e.g. 200ms")
+	progressFlag         = flag.Bool("progress", false, "show a progress bar on stderr while scanning, when it's a terminal")
+	expectedFlag         = flag.Int("expected", 0, "with --progress, the number of resources you expect, to draw a bar with an ETA")
+	concurrencyFlag      = flag.Int("concurrency-per-region", 1, "with --resource-type, scan this many resource types of a region in parallel")
+	sinceLastScanFlag    = flag.Bool("since-last-scan", false, "only print resources added or removed since the last scan with the same regions and filters, then update the cache")
+	serveFlag            = flag.String("serve", "", "run a gRPC inventory service on this address instead, e.g. :9090 (see proto/awslist.proto)")
+	assertNoneFlag       = flag.Bool("assert-none", false, "exit non-zero if any resources are listed after filtering, for CI policy checks")
+	failOnUntaggedFlag   = flag.Bool("fail-on-untagged", false, "exit non-zero if any listed resource lacks one of the --required-tags, printing them to stderr")
+	requiredTagsFlag     = flag.String("required-tags", "", "with --fail-on-untagged, comma separated tag keys every resource must have, e.g. CostCenter,Owner")
+	assertSomeFlag       = flag.Bool("assert-some", false, "exit non-zero if no resources are listed after filtering")
+	timingsFlag          = flag.Bool("timings", false, "print how long each region took, with its page and resource counts, to stderr (also shown with --debug)")
+	debugFlag            = flag.Bool("debug", false, "log debugging details to stderr")
+	warnARNLengthFlag    = flag.Int("warn-arn-length", 0, "warn on stderr about listed resources whose ARN is longer than this many characters")
+	limitPerServiceFlag  = flag.Int("limit-per-service", 0, "only list the first this many resources of each service, for a representative sample of a large account")
+	dedupByFlag          = flag.String("dedup-by", "", "comma separated columns, only list the first resource for each combination of their values, e.g. service,product,id")
+	preflightFlag        = flag.Bool("preflight", false, "check the credentials and that the tagging API answers in every region, then exit without scanning")
+	describeOutputFlag   = flag.Bool("describe-output", false, "print the header or JSON Schema --output would produce with the chosen columns, without scanning anything")
+	interactiveFlag      = flag.Bool("interactive", false, "browse the results in a filterable, sortable table in the terminal")
+	inputFlag            = flag.String("input", "", "re-render the resources saved by an earlier --output json or jsonl from this file (- for stdin) instead of scanning")
+	deterministicFlag    = flag.Bool("deterministic", false, "sort resources by ARN so the output is byte for byte the same between runs, e.g. for snapshots kept in git")
+)
+
+// scan lists the resources of every region and renders them using
+// the requested output format
+func scan(ctx context.Context, cfg aws.Config, regions []string) (err error) {
+	var resources []*SingleResource
+	var emit func(*SingleResource) error
+
+	collect := func(res *SingleResource) error {
+		resources = append(resources, res)
+		return nil
+	}
+
+	columns, err := outputColumns()
+	if err != nil {
+		return err
+	}
+
+	// A broken template should fail before any API calls are made
+	tmpl, err := LoadTemplate(*templateFlag, *templateFileFlag)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range splitList(*humanizeTagFlag) {
+		humanizedTags[k] = true
+	}
+
+	if *inputFlag != "" && (*accountNamesFlag || *enrichFlag || *estimateCostFlag || *commitmentsFlag || (*outputDirFlag != "" && *outputFlag != "csv-per-service") || *sinceLastScanFlag || *resourceTypeFlag != "") {
+		return fmt.Errorf("--input can't be combined with --resolve-account-names, --enrich, --estimate-cost, --include-commitments, --output-dir, --since-last-scan or --resource-type")
+	}
+
+	var accountNames map[string]string
+	if *accountNamesFlag {
+		// Not being allowed to list the organization's accounts isn't
+		// fatal, we just show the raw account ids instead
+		if accountNames, err = ResolveAccountNames(ctx, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: can't resolve account names, showing account ids: %v\n", err)
 		}
-		data = append(data, row)
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Region", "Service", "Product", "ID"})
-	table.SetBorder(true)
-	table.AppendBulk(data)
-	table.Render()
-}
+	if *outputDirFlag != "" {
+		if *outputFileFlag != "" {
+			return fmt.Errorf("--output-file and --output-dir can't be used together")
+		}
+		if err := os.MkdirAll(*outputDirFlag, 0755); err != nil {
+			return err
+		}
+	}
 
-// GetServiceFromArn removes the arn:aws: component string of
-// the name and returns the first keyword that appears, svc
-func ServiceNameFromARN(arn *string) *string {
-	shortArn := strings.Replace(*arn, "arn:aws:", "", -1)
-	sliced := strings.Split(shortArn, ":")
-	return &sliced[0]
-}
+	var checkpoint *Checkpoint
+	if *resumeFlag != "" {
+		if *inputFlag != "" || *concurrencyFlag > 1 {
+			return fmt.Errorf("--resume can't be combined with --input or --concurrency-per-region")
+		}
+		if checkpoint, err = LoadCheckpoint(*resumeFlag); err != nil {
+			return fmt.Errorf("reading --resume checkpoint: %w", err)
+		}
+		if err := checkpoint.Check(ScanArgs(regions)); err != nil {
+			return fmt.Errorf("--resume checkpoint is for another scan: %w", err)
+		}
+		// Appending to a new file would quietly lose what the
+		// interrupted scan listed
+		if checkpoint.Resuming() && *outputFileFlag != "" {
+			if _, err := os.Stat(*outputFileFlag); err != nil {
+				return fmt.Errorf("--resume can't carry on without the output of the interrupted scan: %w", err)
+			}
+		}
+	}
 
-// Short ARN removes the unnecessary info from the ARN we already
-// know at this point like region, account id and the service name.
-func ShortArn(arn *string) string {
-	slicedArn := strings.Split(*arn, ":")
-	shortArn := slicedArn[5:]
-	return strings.Join(shortArn, "/")
-}
+	out := io.Writer(os.Stdout)
+	// SQLite writes the database file itself
+	if *outputFileFlag != "" && *outputFlag != "sqlite" {
+		// A resumed scan only fetches what's left, so it adds to the
+		// output of the interrupted one
+		flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		if checkpoint != nil && checkpoint.Resuming() {
+			flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		}
+		f, err := os.OpenFile(*outputFileFlag, flags, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	// Compressed output is closed even when the scan is cancelled, so
+	// what was written so far is still a valid gzip file
+	if *gzipFlag || strings.HasSuffix(*outputFileFlag, ".gz") {
+		if *outputDirFlag != "" {
+			return fmt.Errorf("--gzip can't be used with --output-dir")
+		}
+		gz := gzip.NewWriter(out)
+		defer func() {
+			if closeErr := gz.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		out = gz
+	}
+
+	var filter Filter
+	if *filterFlag != "" {
+		if filter, err = ParseFilter(*filterFlag); err != nil {
+			return err
+		}
+	}
+
+	if *tagsFilterModeFlag != "all" && *tagsFilterModeFlag != "any" {
+		return fmt.Errorf("--tags-filter-mode must be all or any, got %q", *tagsFilterModeFlag)
+	}
+
+	if *onlyGlobalFlag && *onlyRegionalFlag {
+		return fmt.Errorf("--only-global and --only-regional can't be used together")
+	}
+
+	if *assertNoneFlag && *assertSomeFlag {
+		return fmt.Errorf("--assert-none and --assert-some can't be used together")
+	}
+
+	requiredTags := splitList(*requiredTagsFlag)
+	if *failOnUntaggedFlag && len(requiredTags) == 0 {
+		return fmt.Errorf("--fail-on-untagged needs the --required-tags to check, e.g. --required-tags CostCenter,Owner")
+	}
+
+	if *byCategoryFlag && *summaryByTagFlag != "" {
+		return fmt.Errorf("--by-category and --summary-by-tag can't be used together")
+	}
+	if *tagCoverageFlag != "" && (*byCategoryFlag || *summaryByTagFlag != "") {
+		return fmt.Errorf("--tag-coverage can't be combined with --by-category or --summary-by-tag")
+	}
+	if _, err := ParseCategoryMap(*categoryMapFlag); err != nil {
+		return err
+	}
+
+	if *sortByCountFlag != "asc" && *sortByCountFlag != "desc" {
+		return fmt.Errorf("--sort-by-count must be asc or desc, got %q", *sortByCountFlag)
+	}
+
+	if _, err := ParseIAMWildcard(*iamWildcardFlag); err != nil {
+		return err
+	}
+
+	fields, err := ParseFieldMap(*fieldMapFlag)
+	if err != nil {
+		return err
+	}
+
+	streamed := false
+	switch *outputFlag {
+	case "line":
+		emit, streamed = StreamLines(out, columns), true
+	case "logfmt":
+		emit, streamed = StreamLogfmt(out, columns), true
+	case "arns":
+		emit, streamed = StreamARNs(out), true
+	case "jsonl":
+		// Every resource is written out as it arrives, nothing is buffered
+		emit, streamed = StreamJSONL(out, fields, *jsonFlattenFlag), true
+	case "tf-import-blocks":
+		emit, streamed = StreamTerraformImports(out), true
+	case "tags-csv":
+		emit, streamed = StreamTagsCSV(out), true
+	case "xlsx":
+		if *outputFileFlag == "" && *outputDirFlag == "" {
+			return fmt.Errorf("--output xlsx needs an --output-file to write the spreadsheet to")
+		}
+		fallthrough
+	case "table", "csv", "json", "xml", "html", "iam-resources", "dot", "hash", "tree":
+		emit = collect
+	case "csv-per-service":
+		if *outputDirFlag == "" || *summaryFlag {
+			return fmt.Errorf("--output csv-per-service needs an --output-dir to write the files to, and can't be used with --summary")
+		}
+		emit = collect
+	case "sqlite":
+		if *outputFileFlag == "" || *outputDirFlag != "" || *gzipFlag || *resumeFlag != "" {
+			return fmt.Errorf("--output sqlite needs an --output-file to write the database to, and can't be used with --output-dir, --gzip or --resume")
+		}
+		emit = collect
+	case "influx":
+		if !*summaryFlag {
+			return fmt.Errorf("--output influx only works with --summary")
+		}
+		emit = collect
+	default:
+		return fmt.Errorf("unknown output format %q", *outputFlag)
+	}
+
+	if tmpl != nil {
+		if *outputDirFlag != "" {
+			return fmt.Errorf("--template and --template-file can't be used with --output-dir")
+		}
+		emit, streamed = StreamTemplate(out, tmpl), true
+	}
+
+	// The summary can only be worked out once we have everything, and
+	// per region files are written once their region is done
+	if *summaryFlag || *outputDirFlag != "" || typesCommand {
+		emit, streamed = collect, false
+	}
+
+	if typesCommand && (*summaryFlag || *outputDirFlag != "" || *interactiveFlag || *sinceLastScanFlag || tmpl != nil) {
+		return fmt.Errorf("the types command can't be combined with --summary, --output-dir, --interactive, --since-last-scan or --template")
+	}
+
+	if *sinceLastScanFlag {
+		// A sample would look like everything past the limit was removed
+		if *summaryFlag || *outputDirFlag != "" || *interactiveFlag || tmpl != nil || *limitPerServiceFlag > 0 {
+			return fmt.Errorf("--since-last-scan can't be combined with --summary, --output-dir, --interactive, --template or --limit-per-service")
+		}
+		emit, streamed = collect, false
+	}
+
+	if *interactiveFlag {
+		if *summaryFlag || *outputDirFlag != "" || *outputFileFlag != "" {
+			return fmt.Errorf("--interactive can't be combined with --summary, --output-file or --output-dir")
+		}
+		emit, streamed = collect, false
+	}
+
+	// --deterministic and --region-order hold back what would have been
+	// streamed until the scan is done, so it can be sorted first
+	var replay func(*SingleResource) error
+	if (*deterministicFlag || *regionOrderFlag != "") && streamed {
+		replay, emit = emit, collect
+	}
+
+	// A resumed scan appends what's left to the output of the interrupted
+	// one, and the checkpoint moves on as soon as a page is handed over,
+	// so the output has to be written a line at a time as it comes in
+	if checkpoint != nil {
+		if !resumableOutputs[*outputFlag] || *outputFileFlag == "" {
+			return fmt.Errorf("--resume needs --output line, logfmt, arns or jsonl written to an --output-file")
+		}
+		if !streamed || replay != nil || tmpl != nil || *gzipFlag || strings.HasSuffix(*outputFileFlag, ".gz") || *dedupByFlag != "" || *limitPerServiceFlag > 0 {
+			return fmt.Errorf("--resume can't be combined with --summary, --output-dir, --interactive, --since-last-scan, --template, --deterministic, --region-order, --gzip, --dedup-by or --limit-per-service")
+		}
+	}
+
+	opts := ScanOptions{
+		ResourceTypes:    splitList(*resourceTypeFlag),
+		Breaker:          NewThrottleBreaker(*retryBudgetFlag, *breakerThresholdFlag, *cooloffFlag),
+		PageDelay:        *minPageDelayFlag,
+		EmptyPageRetries: *retryOnEmptyPageFlag,
+	}
+	if *retryLogFlag != "" {
+		retryLog, err := OpenRetryLog(*retryLogFlag)
+		if err != nil {
+			return err
+		}
+		defer retryLog.Close()
+		opts.RetryHook = retryLog.Hook()
+	}
+	services := splitList(*serviceFlag)
+	hasTags := splitList(*hasTagFlag)
+	missingTags := splitList(*missingTagFlag)
+	tagFilters := ParseTagFilters(*tagFlag)
+	approved := map[string]bool{}
+	for _, r := range splitList(*approvedRegionsFlag) {
+		approved[r] = true
+	}
+
+	var dedupBy []string
+	if *dedupByFlag != "" {
+		if dedupBy, err = ParseColumns(*dedupByFlag); err != nil {
+			return fmt.Errorf("--dedup-by: %w", err)
+		}
+	}
+	seen, seenGlobal := map[string]bool{}, map[string]bool{}
+	// A resumed scan carries on from the global resources the interrupted
+	// one listed, they're saved with every page
+	if checkpoint != nil {
+		seenGlobal = checkpoint.Global
+	}
+	perService, truncated := map[string]int{}, map[string]int{}
+
+	var enricher *Enricher
+	if *enrichFlag {
+		enricher = NewEnricher(cfg)
+	}
+	var estimator *CostEstimator
+	if *estimateCostFlag {
+		estimator = NewCostEstimator(cfg)
+	}
+
+	// handle runs every converted resource through the optional
+	// filters and transformations before it's handed to the output
+	var fetched, listed int
+	var longARNs []string
+	var untagged []TagViolation
+
+	var progress *Progress
+	if *progressFlag {
+		progress = NewProgress(*expectedFlag)
+	}
+
+	handle := func(res *SingleResource) error {
+		fetched++
+		progress.Add(DerefNilPointerStrings(res.Region))
+		// Global resources come back from every region scanned, only the
+		// first one is kept and it's put under the global region so
+		// every output agrees with the summary
+		if IsGlobalResource(res) {
+			if seenGlobal[*res.ARN] {
+				return nil
+			}
+			seenGlobal[*res.ARN] = true
+			region := globalRegion
+			res.Region = &region
+		}
+		if len(services) > 0 && !MatchesService(res, services) {
+			return nil
+		}
+		if len(hasTags) > 0 && !res.Tags.HasAll(hasTags) {
+			return nil
+		}
+		if len(missingTags) > 0 && res.Tags.HasAll(missingTags) {
+			return nil
+		}
+		if len(tagFilters) > 0 && !res.Tags.Matches(tagFilters, *tagsFilterModeFlag == "any") {
+			return nil
+		}
+		if (*onlyGlobalFlag && HasARNRegion(res)) || (*onlyRegionalFlag && !HasARNRegion(res)) {
+			return nil
+		}
+		// Global resources don't live in any region, so they can't be in
+		// the wrong one
+		if len(approved) > 0 && (!HasARNRegion(res) || approved[DerefNilPointerStrings(res.Region)]) {
+			return nil
+		}
+		if enricher != nil {
+			enricher.Enrich(ctx, res)
+		}
+		if estimator != nil {
+			estimator.Estimate(ctx, res)
+		}
+		if *friendlyFlag {
+			ApplyFriendlyServiceName(res)
+		}
+		if *accountNamesFlag {
+			ApplyAccountName(res, accountNames)
+		}
+		// --filter runs on what the output shows, so it sees enriched
+		// details, costs, friendly service names and account names
+		if filter != nil && !filter.Match(res) {
+			return nil
+		}
+		if len(dedupBy) > 0 {
+			key := strings.Join(res.row(dedupBy), "\x00")
+			if seen[key] {
+				return nil
+			}
+			seen[key] = true
+		}
+		if *limitPerServiceFlag > 0 {
+			service := DerefNilPointerStrings(res.Service)
+			if perService[service] >= *limitPerServiceFlag {
+				truncated[service]++
+				return nil
+			}
+			perService[service]++
+		}
+		listed++
+		if *warnARNLengthFlag > 0 && len(DerefNilPointerStrings(res.ARN)) > *warnARNLengthFlag {
+			longARNs = append(longARNs, DerefNilPointerStrings(res.ARN))
+		}
+		if *failOnUntaggedFlag {
+			if missing := res.Tags.Missing(requiredTags); len(missing) > 0 {
+				untagged = append(untagged, TagViolation{ARN: DerefNilPointerStrings(res.ARN), Missing: missing})
+			}
+		}
+		return emit(res)
+	}
+
+	// A saved scan goes through the same filters as a live one, there
+	// are just no regions to scan afterwards
+	if *inputFlag != "" {
+		saved, err := ReadInput(*inputFlag)
+		if err != nil {
+			return err
+		}
+		for _, res := range saved {
+			if err := handle(res); err != nil {
+				return err
+			}
+		}
+	}
+
+	var scanErrs ScanErrors
+	var timings []RegionTiming
+
+	// Reserved Instances have no ARNs of their own to take the account from
+	var account string
+	if *commitmentsFlag {
+		if account, err = CallerAccount(ctx, cfg); err != nil {
+			return fmt.Errorf("--include-commitments can't work out the account: %w", err)
+		}
+	}
+	// A resumed scan had them listed before its first page
+	if *commitmentsFlag && (checkpoint == nil || !checkpoint.Resuming()) {
+		plans, err := ListSavingsPlans(ctx, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: --include-commitments can't list the Savings Plans: %v\n", err)
+		}
+		for _, res := range plans {
+			if err := handle(res); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, region := range regions {
+		// Creating the actual AWS client from the SDK, pointed at the
+		// region we're currently scanning
+		r := resourcegroupstaggingapi.NewFromConfig(cfg, func(o *resourcegroupstaggingapi.Options) {
+			o.Region = region
+			o.Retryer = NoThrottleRetryer()
+		})
+
+		if checkpoint != nil {
+			token, done := checkpoint.Next(region)
+			if done {
+				continue
+			}
+			opts.StartToken = token
+			opts.CheckpointHook = func(token string) error {
+				return checkpoint.Save(region, token)
+			}
+		}
+
+		// The page hook can be called from several goroutines when the
+		// resource types are scanned concurrently
+		var pages int64
+		opts.PageHook = func(int, int) bool {
+			atomic.AddInt64(&pages, 1)
+			return !*noPaginateFlag
+		}
+		start, fetchedBefore := time.Now(), fetched
+
+		// A region resumed part way through had them listed already
+		if *commitmentsFlag && opts.StartToken == "" {
+			commitments, err := ListCommitments(ctx, cfg, region, account)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: --include-commitments can't list the Reserved Instances in %s: %v\n", region, err)
+			}
+			for _, res := range commitments {
+				if err := handle(res); err != nil {
+					return err
+				}
+			}
+		}
+
+		if *concurrencyFlag > 1 && len(opts.ResourceTypes) > 1 {
+			err = FetchResourcesConcurrently(ctx, r, region, opts, *concurrencyFlag, handle)
+		} else {
+			err = FetchResources(ctx, r, region, opts, handle)
+		}
+		timings = append(timings, RegionTiming{
+			Region:    region,
+			Pages:     int(atomic.LoadInt64(&pages)),
+			Resources: fetched - fetchedBefore,
+			Duration:  time.Since(start),
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				progress.Done()
+				return ctx.Err()
+			}
+			// A failing region shouldn't hide the ones that worked, so
+			// carry on and report every failure once we're done
+			scanErrs = append(scanErrs, NewRegionError(region, err))
+
+		}
+
+		// Per service files are only written once every region is in
+		if *outputDirFlag != "" && *outputFlag != "csv-per-service" {
+			var regionErrs ScanErrors
+			if err != nil {
+				regionErrs = scanErrs[len(scanErrs)-1:]
+			}
+			if *deterministicFlag {
+				SortByARN(resources)
+			}
+			if err := writeRegionFile(region, resources, columns, fields, regionErrs); err != nil {
+				return err
+			}
+			resources = nil
+		}
 
-// awsEC2 type is created for ARNs belonging to the EC2 service
-type awsEC2 string
+		// Once the retry budget is gone there's no point trying the
+		// remaining regions, render what we've got so far
+		if errors.Is(err, ErrRetryBudgetExhausted) {
+			break
+		}
+	}
 
-// awsECS type is created for ARNs belonging to the ECS service
-type awsECS string
+	progress.Done()
 
-// awsGeneric is a is a generic AWS for services ARNs that don't have
-// a dedicated type within our application.
-type awsGeneric string
+	if checkpoint != nil && len(scanErrs) == 0 {
+		if err := checkpoint.Remove(); err != nil {
+			return err
+		}
+	}
 
-// Generic Resource Handler
-func (aws *awsGeneric) ConverToResource(shortArn, svc, rgn *string) *SingleResource {
-	return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, ID: shortArn}
+	if *deterministicFlag {
+		SortByARN(resources)
+	}
+	if *regionOrderFlag != "" {
+		SortByRegion(resources, *regionOrderFlag)
+	}
+
+	// Finally print the results, unless they've already gone into
+	// the per region files
+	if replay != nil {
+		for _, r := range resources {
+			if err := replay(r); err != nil {
+				return err
+			}
+		}
+	} else if typesCommand {
+		if err := RenderResourceTypes(out, *outputFlag, CountResourceTypes(resources), *jsonPrettyFlag); err != nil {
+			return err
+		}
+	} else if *sinceLastScanFlag {
+		if err := printSinceLastScan(out, regions, resources, columns, scanErrs); err != nil {
+			return err
+		}
+	} else if *interactiveFlag {
+		if err := Browse(resources, columns); err != nil {
+			return err
+		}
+	} else if *outputFlag == "csv-per-service" {
+		if err := writeServiceFiles(resources, columns, fields); err != nil {
+			return err
+		}
+	} else if *outputDirFlag == "" {
+		if err := render(out, resources, columns, fields, scanErrs); err != nil {
+			return err
+		}
+	}
+	if *explainFlag {
+		fmt.Fprintf(os.Stderr, explainNote, fetched, listed)
+	}
+	if len(longARNs) > 0 {
+		fmt.Fprintf(os.Stderr, "warning: %d ARNs are longer than %d characters:\n", len(longARNs), *warnARNLengthFlag)
+		for _, arn := range longARNs {
+			fmt.Fprintf(os.Stderr, "  %s (%d)\n", arn, len(arn))
+		}
+	}
+	if len(truncated) > 0 {
+		printTruncated(os.Stderr, truncated)
+	}
+	if *noPaginateFlag {
+		fmt.Fprintln(os.Stderr, "note: --no-paginate only fetched the first page of each region, there may be more resources")
+	}
+	if *timingsFlag || *debugFlag {
+		RenderTimings(os.Stderr, timings)
+	}
+	var assertErr error
+	switch {
+	case *assertNoneFlag && listed > 0:
+		assertErr = fmt.Errorf("--assert-none: %d matching resources found", listed)
+	case *assertSomeFlag && listed == 0:
+		assertErr = fmt.Errorf("--assert-some: no matching resources found")
+	case len(untagged) > 0:
+		RenderTagViolations(os.Stderr, untagged)
+		assertErr = fmt.Errorf("--fail-on-untagged: %d of %d resources are missing required tags", len(untagged), listed)
+	}
+	// The regions that failed are likely why an assertion did, so they're
+	// reported along with it rather than hidden behind it
+	if assertErr != nil && len(scanErrs) > 0 {
+		return fmt.Errorf("%v, but not every region could be scanned. %v", assertErr, scanErrs)
+	}
+	if assertErr != nil {
+		return assertErr
+	}
+	if len(scanErrs) > 0 {
+		return scanErrs
+	}
+	return nil
 }
 
-// ConvertToRow converts EC2 shortened ARNs to to a SingleResource type
-func (aws *awsEC2) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
-	s := strings.Split(*shortArn, "/")
-	return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &s[1]}
+// describeOutput runs --describe-output, describing the output the other
+// flags ask for
+func describeOutput() error {
+	if *summaryFlag || *templateFlag != "" || *templateFileFlag != "" || typesCommand || tagKeysCommand || tagValuesCommand {
+		return fmt.Errorf("--describe-output can't be combined with --summary, --template or the types, tag-keys and tag-values commands")
+	}
+	columns, err := outputColumns()
+	if err != nil {
+		return err
+	}
+	fields, err := ParseFieldMap(*fieldMapFlag)
+	if err != nil {
+		return err
+	}
+	return DescribeOutput(os.Stdout, *outputFlag, columns, fields, *jsonFlattenFlag, *jsonPrettyFlag)
 }
 
-// ConvertToRow converts ECS shortened ARNs to to a SingleResource type
-func (aws *awsECS) ConvertToResource(shortArn, svc, rgn *string) *SingleResource {
-	s := strings.Split(*shortArn, "/")
-	return &SingleResource{ARN: shortArn, Region: rgn, Service: svc, Product: &s[0], ID: &s[1]}
+// outputColumns works out the columns of the table, line, logfmt, csv,
+// html and xlsx output from --columns or --select, and the flags adding to
+// the default ones
+func outputColumns() ([]string, error) {
+	columns, err := ParseColumns(*columnsFlag)
+	if err != nil {
+		return nil, err
+	}
+	if *selectFlag != "" {
+		return ParseSelect(*selectFlag), nil
+	}
+	if *columnsFlag == "" {
+		if *showPartitionFlag {
+			columns = append([]string{"partition"}, columns...)
+		}
+		if *accountNamesFlag {
+			columns = append(columns, "account-name")
+		}
+		if *estimateCostFlag {
+			columns = append(columns, "monthly-cost")
+		}
+	}
+	return columns, nil
 }
 
-// GetResourceRow shortens the ARN and assigns it to the right
-// service type calling its "ConvertToRow" method. Since we have
-// a default behaviour funneled towards our awsGeneric type, all
-// services will be handled.
-func ConvertArnToSingleResource(arn, svc, rgn *string) *SingleResource {
-	shortArn := ShortArn(arn)
-
-	switch *svc {
-	case "ec2":
-		res := awsEC2(*svc)
-		return res.ConvertToResource(&shortArn, svc, rgn)
-	case "ecs":
-		res := awsECS(*svc)
-		return res.ConvertToResource(&shortArn, svc, rgn)
-	default:
-		res := awsGeneric(*svc)
-		return res.ConverToResource(&shortArn, svc, rgn)
+// printSinceLastScan prints what changed compared to the cached results of
+// the previous scan with the same regions and filters, and caches these
+// results for next time. The very first scan has nothing to compare to so
+// everything shows up as added.
+func printSinceLastScan(w io.Writer, regions []string, resources []*SingleResource, columns []string, errs ScanErrors) error {
+	key := strings.Join([]string{
+		os.Getenv("AWS_PROFILE"),
+		strings.Join(regions, ","),
+		*serviceFlag,
+		*hasTagFlag,
+		*missingTagFlag,
+		*tagFlag,
+		*tagsFilterModeFlag,
+		*approvedRegionsFlag,
+		*filterFlag,
+		*resourceTypeFlag,
+	}, "|")
+	path, err := CachePath(key)
+	if err != nil {
+		return err
+	}
+
+	previous, err := LoadCache(path)
+	if err != nil {
+		return err
 	}
+	added, removed := DiffResources(previous, resources)
+	if err := RenderDiff(w, *outputFlag, added, removed, columns, *jsonPrettyFlag); err != nil {
+		return err
+	}
+
+	// A region that failed would look like all its resources were
+	// removed, so incomplete results never replace the cache
+	if len(errs) > 0 {
+		return nil
+	}
+	return SaveCache(path, resources)
 }
 
-// DerefNilPointerStrings utility func to make sure we don't run into
-// a "nil pointer dereference" issue during runtime.
-func DerefNilPointerStrings(s *string) string {
-	if s == nil {
-		return ""
+// printTruncated notes which services --limit-per-service cut short, and
+// by how many resources
+func printTruncated(w io.Writer, truncated map[string]int) {
+	services := make([]string, 0, len(truncated))
+	for s := range truncated {
+		services = append(services, s)
+	}
+	sort.Strings(services)
+	fmt.Fprintf(w, "note: --limit-per-service %d left out resources of %d services:\n", *limitPerServiceFlag, len(services))
+	for _, s := range services {
+		name := s
+		if name == "" {
+			name = "(no service)"
+		}
+		fmt.Fprintf(w, "  %s: %d more\n", name, truncated[s])
 	}
-	return *s
 }
 
-func main() {
-	var resources []*SingleResource
+// debugf logs to stderr when --debug is set
+func debugf(format string, args ...interface{}) {
+	if *debugFlag {
+		fmt.Fprintf(os.Stderr, "debug: "+format+"\n", args...)
+	}
+}
 
-	var region = os.Args[1]
+// explainNote is printed by --explain once the results are out
+const explainNote = `
+Note: awslist lists resources through the Resource Groups Tagging API, which
+only returns resources of services that support tagging. Resources that can't
+be tagged (and some that have never been tagged, depending on the service)
+won't show up here.
 
-	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(region))
+` + commitmentsNote + `
 
-	// Creating the actual AWS client from the SDK
-	r := resourcegroupstaggingapi.NewFromConfig(cfg)
+%d resources were returned by the API, %d listed after filtering.
+`
 
-	// The results will come paginated, so we create an empty
-	// one outside the next for loop so we can keep updating
-	// it and check if there are still more results to come or
-	// not. We could isolate this function and call it recursively
-	// if we wanted to tidy up our code.
-	var paginationToken string = ""
-	var in *resourcegroupstaggingapi.GetResourcesInput
-	var out *resourcegroupstaggingapi.GetResourcesOutput
+// resumableOutputs are the formats --resume can append to, every resource
+// is a line of its own without headers or state shared between them
+var resumableOutputs = map[string]bool{"line": true, "logfmt": true, "arns": true, "jsonl": true}
 
-	// Let's start an infinite for loop until there are no
-	for {
-		if len(paginationToken) == 0 {
-			in = &resourcegroupstaggingapi.GetResourcesInput{
-				ResourcesPerPage: aws.Int32(50),
+// outputExtensions are the file extensions used by --output-dir
+var outputExtensions = map[string]string{
+	"table":            ".txt",
+	"line":             ".txt",
+	"logfmt":           ".log",
+	"arns":             ".txt",
+	"csv":              ".csv",
+	"json":             ".json",
+	"jsonl":            ".jsonl",
+	"xml":              ".xml",
+	"html":             ".html",
+	"iam-resources":    ".json",
+	"dot":              ".dot",
+	"hash":             ".txt",
+	"xlsx":             ".xlsx",
+	"tf-import-blocks": ".tf",
+	"tags-csv":         ".csv",
+	"influx":           ".txt",
+	"tree":             ".txt",
+}
+
+// writeRegionFile writes the resources of a single region to its own
+// file in --output-dir, e.g. snapshots/us-east-1.json
+func writeRegionFile(region string, resources []*SingleResource, columns []string, fields FieldMap, errs ScanErrors) error {
+	f, err := os.Create(filepath.Join(*outputDirFlag, region+outputExtensions[*outputFlag]))
+	if err != nil {
+		return err
+	}
+
+	// The streaming formats weren't streamed in this case, so they're
+	// written out here along with the buffered ones
+	var emit func(*SingleResource) error
+	if !*summaryFlag {
+		switch *outputFlag {
+		case "arns":
+			emit = StreamARNs(f)
+		case "line":
+			emit = StreamLines(f, columns)
+		case "logfmt":
+			emit = StreamLogfmt(f, columns)
+		case "jsonl":
+			emit = StreamJSONL(f, fields, *jsonFlattenFlag)
+		case "tf-import-blocks":
+			emit = StreamTerraformImports(f)
+		case "tags-csv":
+			emit = StreamTagsCSV(f)
+		}
+	}
+
+	if emit != nil {
+		for _, r := range resources {
+			if err = emit(r); err != nil {
+				break
 			}
-			out, err = r.GetResources(context.Background(), in)
+		}
+	} else {
+		err = render(f, resources, columns, fields, errs)
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// writeServiceFiles writes the resources of every service to its own csv
+// file in --output-dir, e.g. out/ec2.csv, for --output csv-per-service.
+// Files are named after the ARN service code even with --friendly-names.
+func writeServiceFiles(resources []*SingleResource, columns []string, fields FieldMap) error {
+	byService := map[string][]*SingleResource{}
+	for _, r := range resources {
+		service := DerefNilPointerStrings(r.Service)
+		if r.ServiceCode != nil {
+			service = *r.ServiceCode
+		}
+		if service == "" {
+			service = "unknown"
+		}
+		byService[service] = append(byService[service], r)
+	}
+
+	for service, group := range byService {
+		f, err := os.Create(filepath.Join(*outputDirFlag, service+".csv"))
+		if err != nil {
+			return err
+		}
+		err = RenderCSV(f, group, columns, fields, *csvBOMFlag)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// render prints the buffered resources (or their summary) in the
+// requested output format. Streaming formats have already been written.
+func render(w io.Writer, resources []*SingleResource, columns []string, fields FieldMap, errs ScanErrors) error {
+	if *tagCoverageFlag != "" {
+		keys := splitList(*tagCoverageFlag)
+		coverage := SummarizeTagCoverage(resources, keys, *minResourcesFlag, *sortByCountFlag == "asc")
+		return RenderTagCoverage(w, *outputFlag, coverage, keys, errs, *jsonPrettyFlag)
+	}
+	if *summaryFlag {
+		summaries := SummarizeResources(resources, *minResourcesFlag)
+		if *summaryByTagFlag != "" {
+			summaries = SummarizeByTag(resources, *summaryByTagFlag, *minResourcesFlag)
+		}
+		if *byCategoryFlag {
+			categories, err := ParseCategoryMap(*categoryMapFlag)
 			if err != nil {
-				fmt.Println(err)
-			}
-		} else {
-			in = &resourcegroupstaggingapi.GetResourcesInput{
-				ResourcesPerPage: aws.Int32(50),
-				PaginationToken:  &paginationToken,
+				return err
 			}
+			summaries = SummarizeByCategory(resources, categories, *minResourcesFlag)
+		}
+		if *sortByCountFlag == "asc" {
+			SortSummaries(summaries, true)
+		}
+		if *chartFlag && *outputFlag == "table" {
+			return RenderSummaryChart(w, summaries, terminalWidth())
+		}
+		if *outputFlag == "dot" {
+			return RenderDOT(w, summaries)
+		}
+		if *outputFlag == "influx" {
+			return RenderInflux(w, summaries, *summaryByTagFlag, time.Now())
 		}
+		return RenderSummary(w, *outputFlag, summaries, errs, *jsonPrettyFlag)
+	}
 
-		out, err = r.GetResources(context.Background(), in)
+	switch *outputFlag {
+	case "table":
+		PrettyPrintResources(w, resources, columns)
+	case "csv":
+		return RenderCSV(w, resources, columns, fields, *csvBOMFlag)
+	case "json":
+		return RenderJSON(w, resources, errs, fields, *jsonFlattenFlag, *jsonPrettyFlag)
+	case "xml":
+		return RenderXML(w, resources)
+	case "html":
+		return RenderHTML(w, resources, columns, *htmlInteractiveFlag)
+	case "iam-resources":
+		wc, err := ParseIAMWildcard(*iamWildcardFlag)
 		if err != nil {
-			fmt.Println(err)
+			return err
 		}
+		return RenderIAMResources(w, resources, wc, *jsonPrettyFlag)
+	case "dot":
+		return RenderDOT(w, SummarizeResources(resources, *minResourcesFlag))
+	case "hash":
+		return RenderHash(w, resources, *hashAllFlag)
+	case "xlsx":
+		return RenderXLSX(w, resources, columns)
+	case "tree":
+		return RenderTree(w, resources, *regionOrderFlag)
+	case "sqlite":
+		return RenderSQLite(*outputFileFlag, resources)
+	}
+	return nil
+}
 
-		for _, resource := range out.ResourceTagMappingList {
-			svc := ServiceNameFromARN(resource.ResourceARN)
-			rgn := region
+func main() {
+	switch {
+	case len(os.Args) > 1 && os.Args[1] == "types":
+		typesCommand = true
+		flag.CommandLine.Parse(os.Args[2:])
+	case len(os.Args) > 1 && os.Args[1] == "tag-keys":
+		tagKeysCommand = true
+		flag.CommandLine.Parse(os.Args[2:])
+	case len(os.Args) > 1 && os.Args[1] == "tag-values":
+		tagValuesCommand = true
+		flag.CommandLine.Parse(os.Args[2:])
+	default:
+		flag.Parse()
+	}
+	// ndjson is just another name for jsonl
+	if *outputFlag == "ndjson" {
+		*outputFlag = "jsonl"
+	}
+	if *summaryByTagFlag != "" || *byCategoryFlag || *tagCoverageFlag != "" {
+		*summaryFlag = true
+	}
 
-			resources = append(resources, ConvertArnToSingleResource(resource.ResourceARN, svc, &rgn))
+	if *describeOutputFlag {
+		if err := describeOutput(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
 		}
+		return
+	}
 
-		paginationToken = *out.PaginationToken
-		if *out.PaginationToken == "" {
-			break
+	// Re-rendering a saved scan doesn't talk to AWS, so it needs neither
+	// regions nor credentials
+	if *inputFlag != "" {
+		if *watchFlag > 0 || *serveFlag != "" || tagKeysCommand || tagValuesCommand {
+			fmt.Fprintln(os.Stderr, "--input can't be combined with --watch, --serve or the tag-keys and tag-values commands")
+			os.Exit(2)
+		}
+		if err := scan(context.Background(), aws.Config{}, nil); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
+		return
 	}
 
-	// Finally print the results
-	PrettyPrintResources(resources)
+	regionList := *regionFlag
+	if regionList == "" {
+		regionList = flag.Arg(0)
+	}
+	regions := ParseRegions(regionList)
+
+	if *regionsFileFlag != "" {
+		fromFile, err := ReadRegionsFile(*regionsFileFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		regions = append(regions, fromFile...)
+	}
+
+	regions, err := ValidateRegions(regions, !*skipRegionCheckFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if len(regions) == 0 {
+		fmt.Fprintln(os.Stderr, "a region is required, e.g. awslist --region us-east-1")
+		os.Exit(2)
+	}
+	if *regionOrderFlag != "" {
+		regions = OrderRegions(regions, *regionOrderFlag)
+	}
+
+	// Ctrl-C cancels whatever request is in flight and stops watch mode
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(regions[0]),
+		config.WithHTTPClient(NewHTTPClient(*httpTimeoutFlag, *maxIdleConnsFlag)),
+	)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *preflightFlag {
+		if err := Preflight(ctx, os.Stdout, cfg, regions); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if tagKeysCommand {
+		if err := listTagKeys(ctx, cfg, regions); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if tagValuesCommand {
+		if *keyFlag == "" {
+			fmt.Fprintln(os.Stderr, "the tag-values command needs a --key, e.g. awslist tag-values --key Environment")
+			os.Exit(2)
+		}
+		if err := listTagValues(ctx, cfg, regions, *keyFlag); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *serveFlag != "" {
+		if err := Serve(ctx, *serveFlag, cfg, regions); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *watchFlag > 0 {
+		if *interactiveFlag {
+			fmt.Fprintln(os.Stderr, "--watch and --interactive can't be used together")
+			os.Exit(2)
+		}
+		Watch(ctx, *watchFlag, func(ctx context.Context) error {
+			return scan(ctx, cfg, regions)
+		})
+		return
+	}
+
+	if err := scan(ctx, cfg, regions); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
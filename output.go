@@ -0,0 +1,546 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// defaultColumns are the fields shown when --columns isn't given
+var defaultColumns = []string{"region", "service", "product", "id"}
+
+// columnHeaders maps every column --columns accepts to its table header
+var columnHeaders = map[string]string{
+	"partition":    "Partition",
+	"region":       "Region",
+	"account":      "Account",
+	"account-name": "Account Name",
+	"service":      "Service",
+	"product":      "Product",
+	"details":      "Details",
+	"id":           "ID",
+	"arn":          "ARN",
+	"monthly-cost": "Est. Monthly USD",
+}
+
+// ParseColumns turns a comma separated --columns value into a list of
+// columns, falling back to defaultColumns when it's empty
+func ParseColumns(list string) ([]string, error) {
+	columns := splitList(strings.ToLower(list))
+	if len(columns) == 0 {
+		return defaultColumns, nil
+	}
+	for _, c := range columns {
+		if _, ok := columnHeaders[c]; !ok {
+			return nil, fmt.Errorf("unknown column %q", c)
+		}
+	}
+	return columns, nil
+}
+
+// ParseSelect turns a --select value like "service,id,tags.CostCenter"
+// into a list of field paths. Unlike ParseColumns nothing is rejected,
+// paths that don't resolve to anything just come out empty. Tag keys
+// keep their case, everything else is matched case insensitively.
+func ParseSelect(list string) []string {
+	paths := splitList(list)
+	for i, p := range paths {
+		if !strings.HasPrefix(p, tagFieldPrefix) {
+			paths[i] = strings.ToLower(p)
+		}
+	}
+	return paths
+}
+
+// tagFieldPrefix is how field paths reach into the Tags map
+const tagFieldPrefix = "tags."
+
+// Column returns the value of the named column for this resource. Besides
+// the plain columns, "tags.<key>" resolves to the value of that tag.
+func (r *SingleResource) Column(name string) string {
+	switch name {
+	case "partition":
+		return DerefNilPointerStrings(r.Partition)
+	case "region":
+		return DerefNilPointerStrings(r.Region)
+	case "account":
+		return DerefNilPointerStrings(r.Account)
+	case "account-name":
+		return DerefNilPointerStrings(r.AccountName)
+	case "service":
+		return DerefNilPointerStrings(r.Service)
+	case "product":
+		return DerefNilPointerStrings(r.Product)
+	case "details":
+		return DerefNilPointerStrings(r.Details)
+	case "id":
+		return DerefNilPointerStrings(r.ID)
+	case "arn":
+		return DerefNilPointerStrings(r.ARN)
+	case "monthly-cost":
+		return DerefNilPointerStrings(r.MonthlyCost)
+	}
+	if strings.HasPrefix(name, tagFieldPrefix) {
+		key := strings.TrimPrefix(name, tagFieldPrefix)
+		if humanizedTags[key] {
+			return HumanizeBytes(r.Tags[key])
+		}
+		return r.Tags[key]
+	}
+	return ""
+}
+
+// humanizedTags are the tags whose columns show their value as a size
+// with --humanize-tag
+var humanizedTags = map[string]bool{}
+
+// byteUnits are the units HumanizeBytes picks from
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// HumanizeBytes turns a number of bytes into a size like 1 GiB or
+// 1.5 KiB. Anything that isn't a number is returned as is.
+func HumanizeBytes(value string) string {
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || n < 0 {
+		return value
+	}
+	unit := 0
+	for n >= 1024 && unit < len(byteUnits)-1 {
+		n /= 1024
+		unit++
+	}
+	size := strings.TrimSuffix(strconv.FormatFloat(n, 'f', 1, 64), ".0")
+	return size + " " + byteUnits[unit]
+}
+
+// FieldMap renames fields in the json, jsonl and csv output, from the
+// column name (or JSON key) to the name the output should use instead
+type FieldMap map[string]string
+
+// jsonFieldNames maps the fields --field-map can rename to their keys in
+// the JSON output
+var jsonFieldNames = map[string]string{
+	"partition":    "partition",
+	"region":       "region",
+	"account":      "account",
+	"account-name": "accountName",
+	"accountname":  "accountName",
+	"service":      "service",
+	"servicecode":  "serviceCode",
+	"product":      "product",
+	"details":      "details",
+	"id":           "id",
+	"arn":          "arn",
+	"tags":         "tags",
+	"monthly-cost": "estimatedMonthlyCost",
+}
+
+// ParseFieldMap parses a --field-map value like
+// "id=resource_id,arn=resource_arn"
+func ParseFieldMap(list string) (FieldMap, error) {
+	m := FieldMap{}
+	for _, pair := range splitList(list) {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, fmt.Errorf("--field-map entries look like field=new_name, got %q", pair)
+		}
+		key, ok := jsonFieldNames[strings.ToLower(kv[0])]
+		if !ok {
+			return nil, fmt.Errorf("--field-map: unknown field %q", kv[0])
+		}
+		m[key] = kv[1]
+	}
+	return m, nil
+}
+
+// header returns the csv header of the column, renamed if asked to
+func (m FieldMap) header(column, fallback string) string {
+	if name, ok := m[jsonFieldNames[column]]; ok {
+		return name
+	}
+	return fallback
+}
+
+// apply returns what to encode as JSON for the resource, the resource
+// itself unless some of its keys have to be renamed. With flatten set the
+// tags become top level tag_<key> keys instead of a nested object.
+func (m FieldMap) apply(r *SingleResource, flatten bool) (interface{}, error) {
+	if len(m) == 0 && !flatten {
+		return r, nil
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	if flatten {
+		delete(fields, "tags")
+		for k, v := range r.Tags {
+			if fields["tag_"+k], err = json.Marshal(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for from, to := range m {
+		if v, ok := fields[from]; ok {
+			delete(fields, from)
+			fields[to] = v
+		}
+	}
+	return fields, nil
+}
+
+// columnHeaderRow returns the headers of the given columns
+func columnHeaderRow(columns []string) []string {
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		switch h, ok := columnHeaders[c]; {
+		case ok:
+			header[i] = h
+		case strings.HasPrefix(c, tagFieldPrefix):
+			header[i] = strings.TrimPrefix(c, tagFieldPrefix)
+		default:
+			header[i] = c
+		}
+	}
+	return header
+}
+
+// row returns the values of the given columns for this resource
+func (r *SingleResource) row(columns []string) []string {
+	row := make([]string, len(columns))
+	for i, c := range columns {
+		row[i] = r.Column(c)
+	}
+	return row
+}
+
+// PrettyPrintResources renders the resources as a bordered table
+func PrettyPrintResources(w io.Writer, resources []*SingleResource, columns []string) {
+	var data [][]string
+
+	for _, r := range resources {
+		data = append(data, r.row(columns))
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(columnHeaderRow(columns))
+	table.SetBorder(true)
+	table.AppendBulk(data)
+	table.Render()
+}
+
+// StreamLines returns a callback writing each resource it receives as a
+// single region/service/product/id style line, no table chrome, which
+// keeps huge listings fast and grep friendly.
+func StreamLines(w io.Writer, columns []string) func(*SingleResource) error {
+	return func(r *SingleResource) error {
+		_, err := fmt.Fprintln(w, strings.Join(r.row(columns), "/"))
+		return err
+	}
+}
+
+// StreamLogfmt returns a callback writing each resource it receives as a
+// logfmt line of its columns, e.g.
+//
+//	region=us-east-1 service=ec2 product=instance id=i-123
+//
+// for log pipelines that take logfmt. Values with spaces, quotes or = in
+// them are quoted.
+func StreamLogfmt(w io.Writer, columns []string) func(*SingleResource) error {
+	keys := make([]string, len(columns))
+	for i, c := range columns {
+		keys[i] = logfmtKey(c)
+	}
+	return func(r *SingleResource) error {
+		pairs := make([]string, len(columns))
+		for i, v := range r.row(columns) {
+			pairs[i] = keys[i] + "=" + logfmtValue(v)
+		}
+		_, err := fmt.Fprintln(w, strings.Join(pairs, " "))
+		return err
+	}
+}
+
+// logfmtKey turns a column into a logfmt key, replacing the characters
+// keys can't have, which only tag keys like tags.Cost Center could bring
+func logfmtKey(column string) string {
+	return strings.Map(func(c rune) rune {
+		if c <= ' ' || c == '=' || c == '"' {
+			return '_'
+		}
+		return c
+	}, column)
+}
+
+// logfmtValue quotes a value when it wouldn't read back as a single
+// logfmt value otherwise
+func logfmtValue(v string) string {
+	if strings.IndexFunc(v, func(c rune) bool { return c <= ' ' || c == '=' || c == '"' || c == 0x7f }) >= 0 {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// OutputSchemaVersion is written as "schemaVersion" in the JSON documents.
+// It's bumped whenever a field of the JSON, jsonl or csv output is renamed,
+// removed or changes type. New fields can be added without a bump.
+const OutputSchemaVersion = 1
+
+// jsonDocument is the top level object written by RenderJSON
+type jsonDocument struct {
+	SchemaVersion int           `json:"schemaVersion"`
+	Resources     []interface{} `json:"resources"`
+	Errors        ScanErrors    `json:"errors,omitempty"`
+}
+
+// RenderJSON writes the resources as a single JSON document, along with
+// any regions that couldn't be scanned under "errors"
+func RenderJSON(w io.Writer, resources []*SingleResource, errs ScanErrors, fields FieldMap, flatten, pretty bool) error {
+	doc := jsonDocument{SchemaVersion: OutputSchemaVersion, Resources: make([]interface{}, len(resources)), Errors: errs}
+	for i, r := range resources {
+		v, err := fields.apply(r, flatten)
+		if err != nil {
+			return err
+		}
+		doc.Resources[i] = v
+	}
+	return jsonEncoder(w, pretty).Encode(doc)
+}
+
+// jsonEncoder returns a compact encoder for piping, or an indented one
+// for reading
+func jsonEncoder(w io.Writer, pretty bool) *json.Encoder {
+	enc := json.NewEncoder(w)
+	if pretty {
+		enc.SetIndent("", "  ")
+	}
+	return enc
+}
+
+// utf8BOM is the byte order mark Excel needs to spot UTF-8 CSV files
+const utf8BOM = "\xef\xbb\xbf"
+
+// RenderCSV writes the resources as CSV with a header row. With bom set
+// the output starts with a UTF-8 byte order mark so Excel on Windows
+// doesn't mangle non-ASCII tag values.
+func RenderCSV(w io.Writer, resources []*SingleResource, columns []string, fields FieldMap, bom bool) error {
+	if bom {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return err
+		}
+	}
+
+	header := columnHeaderRow(columns)
+	for i, c := range columns {
+		header[i] = fields.header(c, header[i])
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range resources {
+		if err := cw.Write(r.row(columns)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// StreamTagsCSV returns a callback writing the tags of each resource it
+// receives as arn,tag_key,tag_value CSV rows, one per tag, to load into
+// a table of their own next to the resources. The header is written along
+// with the first resource.
+func StreamTagsCSV(w io.Writer) func(*SingleResource) error {
+	cw := csv.NewWriter(w)
+	started := false
+	return func(r *SingleResource) error {
+		if !started {
+			started = true
+			if err := cw.Write([]string{"arn", "tag_key", "tag_value"}); err != nil {
+				return err
+			}
+		}
+		arn := DerefNilPointerStrings(r.ARN)
+		for _, k := range r.Tags.Keys() {
+			if err := cw.Write([]string{arn, k, r.Tags[k]}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+}
+
+// StreamARNs returns a callback writing the full ARN of each resource it
+// receives on its own line and nothing else, ready for xargs
+func StreamARNs(w io.Writer) func(*SingleResource) error {
+	return func(r *SingleResource) error {
+		_, err := fmt.Fprintln(w, DerefNilPointerStrings(r.ARN))
+		return err
+	}
+}
+
+// StreamJSONL returns a callback writing each resource it receives as a
+// compact JSON object on its own line, which lets tools like jq process
+// the results incrementally while the scan is still running.
+func StreamJSONL(w io.Writer, fields FieldMap, flatten bool) func(*SingleResource) error {
+	enc := json.NewEncoder(w)
+	return func(r *SingleResource) error {
+		v, err := fields.apply(r, flatten)
+		if err != nil {
+			return err
+		}
+		return enc.Encode(v)
+	}
+}
+
+// xmlResources is the document root used by RenderXML
+type xmlResources struct {
+	XMLName   xml.Name          `xml:"resources"`
+	Resources []*SingleResource `xml:"resource"`
+}
+
+// RenderXML writes the resources as a <resources><resource>...</resource></resources>
+// document, leaving out any fields we don't have a value for.
+func RenderXML(w io.Writer, resources []*SingleResource) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(xmlResources{Resources: resources}); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// IAMWildcard says which ARN segments RenderIAMResources replaces with *
+type IAMWildcard struct {
+	Account bool
+	Region  bool
+}
+
+// ParseIAMWildcard parses a comma separated list of ARN segments to
+// wildcard, account and/or region
+func ParseIAMWildcard(list string) (IAMWildcard, error) {
+	var wc IAMWildcard
+	for _, f := range splitList(list) {
+		switch f {
+		case "account":
+			wc.Account = true
+		case "region":
+			wc.Region = true
+		default:
+			return wc, fmt.Errorf("--iam-wildcard only supports account and region, got %q", f)
+		}
+	}
+	return wc, nil
+}
+
+// RenderIAMResources writes the ARNs of the resources as a sorted JSON
+// array without duplicates, ready to paste into the Resource field of an
+// IAM policy. Wildcarding the account or region also merges the ARNs that
+// only differed there.
+func RenderIAMResources(w io.Writer, resources []*SingleResource, wc IAMWildcard, pretty bool) error {
+	seen := map[string]bool{}
+	arns := []string{}
+	for _, r := range resources {
+		arn := DerefNilPointerStrings(r.ARN)
+		s := strings.SplitN(arn, ":", 6)
+		if len(s) == 6 {
+			// Segments that are empty, like the region of global
+			// resources, have to stay empty to match
+			if wc.Region && s[3] != "" {
+				s[3] = "*"
+			}
+			if wc.Account && s[4] != "" {
+				s[4] = "*"
+			}
+			arn = strings.Join(s, ":")
+		}
+		if arn == "" || seen[arn] {
+			continue
+		}
+		seen[arn] = true
+		arns = append(arns, arn)
+	}
+	sort.Strings(arns)
+
+	return jsonEncoder(w, pretty).Encode(arns)
+}
+
+// SortByARN orders the resources by their full ARN, in place
+func SortByARN(resources []*SingleResource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return DerefNilPointerStrings(resources[i].ARN) < DerefNilPointerStrings(resources[j].ARN)
+	})
+}
+
+// SortByRegion groups the resources by region in the --region-order
+// order, in place. It's stable, so within a region they keep their order.
+func SortByRegion(resources []*SingleResource, order string) {
+	less := regionLess(order)
+	sort.SliceStable(resources, func(i, j int) bool {
+		return less(DerefNilPointerStrings(resources[i].Region), DerefNilPointerStrings(resources[j].Region))
+	})
+}
+
+// RenderHash writes a SHA-256 of the resources, to cheaply tell whether
+// anything changed between two scans. By default only the sorted, unique
+// ARNs are hashed. With all set every field is, each resource as JSON
+// (which sorts the tags) in ARN order, so tag changes show up too.
+// Resources sharing an ARN are ordered by their JSON, so the order they
+// came in never changes the hash.
+func RenderHash(w io.Writer, resources []*SingleResource, all bool) error {
+	type line struct{ arn, text string }
+	lines := make([]line, 0, len(resources))
+	for _, r := range resources {
+		l := line{arn: DerefNilPointerStrings(r.ARN)}
+		if all {
+			b, err := json.Marshal(r)
+			if err != nil {
+				return err
+			}
+			l.text = string(b)
+		}
+		lines = append(lines, l)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].arn != lines[j].arn {
+			return lines[i].arn < lines[j].arn
+		}
+		return lines[i].text < lines[j].text
+	})
+
+	h := sha256.New()
+	for i, l := range lines {
+		if !all {
+			if i > 0 && l.arn == lines[i-1].arn {
+				continue
+			}
+			fmt.Fprintln(h, l.arn)
+			continue
+		}
+		fmt.Fprintln(h, l.text)
+	}
+
+	_, err := fmt.Fprintf(w, "%x\n", h.Sum(nil))
+	return err
+}
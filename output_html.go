@@ -0,0 +1,92 @@
+package main
+
+import (
+	"html/template"
+	"io"
+)
+
+// htmlReport is the page written by --output html. With --html-interactive
+// a small inline script adds a search box and sortable columns, so the file
+// still works on its own without a server or any CDN.
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>awslist</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #eee; }
+{{- if .Interactive}}
+th { cursor: pointer; user-select: none; }
+#search { margin-bottom: 1em; padding: 4px; width: 30em; }
+{{- end}}
+</style>
+</head>
+<body>
+<h1>awslist</h1>
+<p><span id="count">{{len .Rows}}</span> resources</p>
+{{- if .Interactive}}
+<input id="search" type="search" placeholder="Filter...">
+{{- end}}
+<table id="resources">
+<thead><tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+{{- if .Interactive}}
+<script>
+(function () {
+  var table = document.getElementById("resources");
+  var body = table.tBodies[0];
+  var rows = Array.prototype.slice.call(body.rows);
+  var search = document.getElementById("search");
+  var count = document.getElementById("count");
+
+  search.addEventListener("input", function () {
+    var q = search.value.toLowerCase();
+    var shown = 0;
+    rows.forEach(function (row) {
+      var match = row.textContent.toLowerCase().indexOf(q) !== -1;
+      row.style.display = match ? "" : "none";
+      if (match) shown++;
+    });
+    count.textContent = shown;
+  });
+
+  var sortCol = -1, ascending = true;
+  Array.prototype.forEach.call(table.tHead.rows[0].cells, function (th, col) {
+    th.addEventListener("click", function () {
+      ascending = sortCol === col ? !ascending : true;
+      sortCol = col;
+      rows.sort(function (a, b) {
+        var x = a.cells[col].textContent, y = b.cells[col].textContent;
+        return ascending ? x.localeCompare(y) : y.localeCompare(x);
+      });
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+})();
+</script>
+{{- end}}
+</body>
+</html>
+`))
+
+// RenderHTML writes the resources as a self contained HTML page, with
+// client side filtering and sorting when interactive is set
+func RenderHTML(w io.Writer, resources []*SingleResource, columns []string, interactive bool) error {
+	var rows [][]string
+	for _, r := range resources {
+		rows = append(rows, r.row(columns))
+	}
+	return htmlReport.Execute(w, struct {
+		Headers     []string
+		Rows        [][]string
+		Interactive bool
+	}{columnHeaderRow(columns), rows, interactive})
+}
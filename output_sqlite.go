@@ -0,0 +1,75 @@
+//go:build sqlite
+// +build sqlite
+
+package main
+
+import (
+	"database/sql"
+	"os"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema are the tables --output sqlite creates, the resources and
+// their tags one row per tag, to join on arn
+const sqliteSchema = `
+CREATE TABLE resources (region TEXT, service TEXT, product TEXT, id TEXT, details TEXT, arn TEXT, account TEXT);
+CREATE INDEX resources_arn ON resources (arn);
+CREATE TABLE tags (arn TEXT, key TEXT, value TEXT);
+CREATE INDEX tags_arn ON tags (arn);
+CREATE INDEX tags_key ON tags (key, value);
+`
+
+// RenderSQLite writes the resources to a new SQLite database at path,
+// replacing whatever file was there, all in a single transaction
+func RenderSQLite(path string, resources []*SingleResource) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	insertResource, err := tx.Prepare(`INSERT INTO resources (region, service, product, id, details, arn, account) VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	insertTag, err := tx.Prepare(`INSERT INTO tags (arn, key, value) VALUES (?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range resources {
+		arn := DerefNilPointerStrings(r.ARN)
+		_, err := insertResource.Exec(
+			DerefNilPointerStrings(r.Region),
+			DerefNilPointerStrings(r.Service),
+			DerefNilPointerStrings(r.Product),
+			DerefNilPointerStrings(r.ID),
+			DerefNilPointerStrings(r.Details),
+			arn,
+			DerefNilPointerStrings(r.Account),
+		)
+		if err != nil {
+			return err
+		}
+		for _, k := range r.Tags.Keys() {
+			if _, err := insertTag.Exec(arn, k, r.Tags[k]); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
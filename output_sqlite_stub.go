@@ -0,0 +1,12 @@
+//go:build !sqlite
+// +build !sqlite
+
+package main
+
+import "errors"
+
+// RenderSQLite is only available when built with the sqlite tag, which
+// keeps the SQLite driver out of the default binary
+func RenderSQLite(path string, resources []*SingleResource) error {
+	return errors.New("sqlite output isn't available in this build, rebuild with -tags sqlite")
+}
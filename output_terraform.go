@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// tfImport says how a kind of resource is imported into Terraform, the
+// resource type and the import id terraform expects for it
+type tfImport struct {
+	Type string
+	ID   func(r *SingleResource, id string) string
+}
+
+// The import ids Terraform wants, worked out from the resource and the id
+// parsed out of its ARN
+var (
+	tfParsedID = func(r *SingleResource, id string) string { return id }
+	tfARN      = func(r *SingleResource, id string) string { return DerefNilPointerStrings(r.ARN) }
+	tfName     = func(r *SingleResource, id string) string { return path.Base(id) }
+	tfQueueURL = func(r *SingleResource, id string) string {
+		return fmt.Sprintf("https://sqs.%s.amazonaws.com/%s/%s", DerefNilPointerStrings(r.Region), DerefNilPointerStrings(r.Account), id)
+	}
+)
+
+// tfImports are the resources --output tf-import-blocks knows how to
+// import, keyed by ARN service code and resource type
+var tfImports = map[string]tfImport{
+	"ec2:instance":                      {"aws_instance", tfParsedID},
+	"ec2:vpc":                           {"aws_vpc", tfParsedID},
+	"ec2:subnet":                        {"aws_subnet", tfParsedID},
+	"ec2:security-group":                {"aws_security_group", tfParsedID},
+	"ec2:volume":                        {"aws_ebs_volume", tfParsedID},
+	"ec2:internet-gateway":              {"aws_internet_gateway", tfParsedID},
+	"ec2:natgateway":                    {"aws_nat_gateway", tfParsedID},
+	"ec2:route-table":                   {"aws_route_table", tfParsedID},
+	"ec2:elastic-ip":                    {"aws_eip", tfParsedID},
+	"s3:":                               {"aws_s3_bucket", tfParsedID},
+	"lambda:function":                   {"aws_lambda_function", tfParsedID},
+	"dynamodb:table":                    {"aws_dynamodb_table", tfParsedID},
+	"sqs:queue":                         {"aws_sqs_queue", tfQueueURL},
+	"sns:topic":                         {"aws_sns_topic", tfARN},
+	"rds:db":                            {"aws_db_instance", tfParsedID},
+	"rds:cluster":                       {"aws_rds_cluster", tfParsedID},
+	"iam:role":                          {"aws_iam_role", tfName},
+	"iam:user":                          {"aws_iam_user", tfName},
+	"iam:policy":                        {"aws_iam_policy", tfARN},
+	"kms:key":                           {"aws_kms_key", tfParsedID},
+	"ecr:repository":                    {"aws_ecr_repository", tfParsedID},
+	"logs:log-group":                    {"aws_cloudwatch_log_group", tfParsedID},
+	"elasticloadbalancing:loadbalancer": {"aws_lb", tfARN},
+	"elasticloadbalancing:targetgroup":  {"aws_lb_target_group", tfARN},
+	"secretsmanager:secret":             {"aws_secretsmanager_secret", tfARN},
+	"states:stateMachine":               {"aws_sfn_state_machine", tfARN},
+	"cloudtrail:trail":                  {"aws_cloudtrail", tfARN},
+	"ecs:cluster":                       {"aws_ecs_cluster", tfParsedID},
+	"elasticache:cluster":               {"aws_elasticache_cluster", tfParsedID},
+	"cloudfront:distribution":           {"aws_cloudfront_distribution", tfParsedID},
+	"route53:hostedzone":                {"aws_route53_zone", tfParsedID},
+}
+
+// tfResourceType returns the resource type and id of r. Resources that
+// went through the generic converter have neither Product nor a plain ID,
+// their ID is still type/id.
+func tfResourceType(r *SingleResource) (string, string) {
+	product, id := DerefNilPointerStrings(r.Product), DerefNilPointerStrings(r.ID)
+	if product == "" {
+		if s := strings.SplitN(id, "/", 2); len(s) == 2 {
+			return s[0], s[1]
+		}
+	}
+	return product, id
+}
+
+// tfLabel turns an id into a valid Terraform resource name
+func tfLabel(id string) string {
+	label := []byte(id)
+	for i, c := range label {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			label[i] = '_'
+		}
+	}
+	if len(label) == 0 || !(label[0] >= 'a' && label[0] <= 'z' || label[0] >= 'A' && label[0] <= 'Z' || label[0] == '_') {
+		return "r_" + string(label)
+	}
+	return string(label)
+}
+
+// StreamTerraformImports returns a callback writing a Terraform 1.5+
+// import block for each resource it receives. The address is only a guess
+// from the resource's id, so it's worth renaming before applying.
+// Resources awslist doesn't know how to import, and Lambda aliases and
+// versions, are written as commented out stubs to fill in by hand.
+func StreamTerraformImports(w io.Writer) func(*SingleResource) error {
+	seen := map[string]int{}
+	return func(r *SingleResource) error {
+		arn := DerefNilPointerStrings(r.ARN)
+		code := DerefNilPointerStrings(r.ServiceCode)
+		if code == "" {
+			code = DerefNilPointerStrings(r.Service)
+		}
+		product, id := tfResourceType(r)
+
+		imp, ok := tfImports[code+":"+product]
+		if !ok || DerefNilPointerStrings(r.Details) != "" && code == "lambda" {
+			_, err := fmt.Fprintf(w, "# %s\n# import {\n#   to = <resource type>.<name>\n#   id = %q\n# }\n\n", arn, id)
+			return err
+		}
+
+		address := imp.Type + "." + tfLabel(path.Base(id))
+		if seen[address]++; seen[address] > 1 {
+			address = fmt.Sprintf("%s_%d", address, seen[address])
+		}
+		_, err := fmt.Fprintf(w, "# %s\nimport {\n  to = %s\n  id = %q\n}\n\n", arn, address, imp.ID(r, id))
+		return err
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// testResource converts arn the way a scan of region would, with tags
+func testResource(arn, region string, tags Tags) *SingleResource {
+	res := ConvertArnToSingleResource(&arn, ServiceNameFromARN(&arn), &region)
+	res.Tags = tags
+	return res
+}
+
+func hashOf(t *testing.T, resources []*SingleResource, all bool) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := RenderHash(&buf, resources, all); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestRenderHashIgnoresOrder(t *testing.T) {
+	resources := []*SingleResource{
+		testResource("arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0", "us-east-1", Tags{"Team": "payments", "Environment": "prod"}),
+		testResource("arn:aws:sqs:eu-west-1:123456789012:jobs", "eu-west-1", nil),
+		testResource("arn:aws:s3:::my-bucket", "us-east-1", Tags{"Owner": "data"}),
+		// The same bucket listed from another region
+		testResource("arn:aws:s3:::my-bucket", "eu-west-1", Tags{"Owner": "data"}),
+		testResource("arn:aws:lambda:us-east-1:123456789012:function:my-fn", "us-east-1", Tags{"a": "1", "b": "2", "c": "3"}),
+	}
+
+	for _, all := range []bool{false, true} {
+		want := hashOf(t, resources, all)
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < 20; i++ {
+			shuffled := append([]*SingleResource(nil), resources...)
+			rnd.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+			if got := hashOf(t, shuffled, all); got != want {
+				t.Fatalf("all=%v: shuffled resources hash to %s, want %s", all, got, want)
+			}
+		}
+	}
+
+	// Only --hash-all notices a tag change
+	changed := append([]*SingleResource(nil), resources...)
+	changed[1] = testResource("arn:aws:sqs:eu-west-1:123456789012:jobs", "eu-west-1", Tags{"Owner": "ops"})
+	if hashOf(t, changed, false) != hashOf(t, resources, false) {
+		t.Error("a tag change changed the ARN only hash")
+	}
+	if hashOf(t, changed, true) == hashOf(t, resources, true) {
+		t.Error("a tag change didn't change the --hash-all hash")
+	}
+
+	// Duplicate ARNs are hashed once by default
+	if hashOf(t, resources[:3], false) != hashOf(t, resources[:4], false) {
+		t.Error("a duplicate ARN changed the ARN only hash")
+	}
+}
+
+func TestLogfmtValue(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"i-0123456789abcdef0", "i-0123456789abcdef0"},
+		{"arn:aws:s3:::my-bucket/a/b", "arn:aws:s3:::my-bucket/a/b"},
+		{"", ""},
+		{"two words", `"two words"`},
+		{" leading", `" leading"`},
+		{"tab\there", `"tab\there"`},
+		{"new\nline", `"new\nline"`},
+		{"a=b", `"a=b"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`"`, `"\""`},
+		{`back\slash`, `back\slash`},
+		{`back\slash and space`, `"back\\slash and space"`},
+		{"del\x7f", `"del\x7f"`},
+		{"café", "café"},
+	}
+	for _, tt := range tests {
+		if got := logfmtValue(tt.in); got != tt.want {
+			t.Errorf("logfmtValue(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLogfmtKey(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"region", "region"},
+		{"account-name", "account-name"},
+		{"tags.Environment", "tags.Environment"},
+		{"tags.Cost Center", "tags.Cost_Center"},
+		{"tags.a=b", "tags.a_b"},
+		{`tags."quoted"`, "tags._quoted_"},
+		{"tags.tab\there", "tags.tab_here"},
+	}
+	for _, tt := range tests {
+		if got := logfmtKey(tt.in); got != tt.want {
+			t.Errorf("logfmtKey(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStreamLogfmt(t *testing.T) {
+	var buf bytes.Buffer
+	emit := StreamLogfmt(&buf, []string{"region", "id", "details", "tags.Cost Center", "tags.Note"})
+	res := testResource("arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0", "us-east-1", Tags{"Cost Center": "R&D team", "Note": `a="b"`})
+	if err := emit(res); err != nil {
+		t.Fatal(err)
+	}
+	want := `region=us-east-1 id=i-0123456789abcdef0 details= tags.Cost_Center="R&D team" tags.Note="a=\"b\""` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %s, want %s", strings.TrimSpace(got), strings.TrimSpace(want))
+	}
+}
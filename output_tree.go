@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// RenderTree writes the resources as an indented tree of regions, their
+// services and the resources of each, with the number of resources at
+// every level:
+//
+//	us-east-1 (3)
+//	├── ec2 (2)
+//	│   ├── instance/i-0abc
+//	│   └── volume/vol-0def
+//	└── sqs (1)
+//	    └── queue/orders
+//
+// Regions are ordered as --region-order says, services by name.
+func RenderTree(w io.Writer, resources []*SingleResource, regionOrder string) error {
+	byRegion := map[string]map[string][]*SingleResource{}
+	for _, r := range resources {
+		region, service := DerefNilPointerStrings(r.Region), DerefNilPointerStrings(r.Service)
+		if byRegion[region] == nil {
+			byRegion[region] = map[string][]*SingleResource{}
+		}
+		byRegion[region][service] = append(byRegion[region][service], r)
+	}
+
+	regions := make([]string, 0, len(byRegion))
+	for region := range byRegion {
+		regions = append(regions, region)
+	}
+	less := regionLess(regionOrder)
+	sort.Slice(regions, func(i, j int) bool { return less(regions[i], regions[j]) })
+
+	var b strings.Builder
+	for _, region := range regions {
+		services := make([]string, 0, len(byRegion[region]))
+		count := 0
+		for service, rs := range byRegion[region] {
+			services = append(services, service)
+			count += len(rs)
+		}
+		sort.Strings(services)
+
+		fmt.Fprintf(&b, "%s (%d)\n", region, count)
+		for i, service := range services {
+			rs := byRegion[region][service]
+			branch, indent := treeBranch(i == len(services)-1)
+			fmt.Fprintf(&b, "%s%s (%d)\n", branch, service, len(rs))
+			for j, r := range rs {
+				leaf, _ := treeBranch(j == len(rs)-1)
+				fmt.Fprintf(&b, "%s%s%s\n", indent, leaf, treeLabel(r))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// treeBranch returns the branch drawn in front of a tree node, and the
+// indentation of its children
+func treeBranch(last bool) (string, string) {
+	if last {
+		return "└── ", "    "
+	}
+	return "├── ", "│   "
+}
+
+// treeLabel is how a resource shows up in the tree, product/id with its
+// details if it has any
+func treeLabel(r *SingleResource) string {
+	label := DerefNilPointerStrings(r.ID)
+	if product := DerefNilPointerStrings(r.Product); product != "" {
+		label = product + "/" + label
+	}
+	if details := DerefNilPointerStrings(r.Details); details != "" {
+		label += " (" + details + ")"
+	}
+	return label
+}
@@ -0,0 +1,63 @@
+//go:build xlsx
+// +build xlsx
+
+package main
+
+import (
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// frozenHeader freezes the first row of a sheet so the headers stay put
+// while scrolling
+const frozenHeader = `{"freeze":true,"split":false,"x_split":0,"y_split":1,"top_left_cell":"A2","active_pane":"bottomLeft"}`
+
+// RenderXLSX writes the resources as an Excel spreadsheet with one sheet
+// per region, each with a frozen header row
+func RenderXLSX(w io.Writer, resources []*SingleResource, columns []string) error {
+	f := excelize.NewFile()
+
+	var regions []string
+	byRegion := map[string][]*SingleResource{}
+	for _, r := range resources {
+		rgn := DerefNilPointerStrings(r.Region)
+		if _, ok := byRegion[rgn]; !ok {
+			regions = append(regions, rgn)
+		}
+		byRegion[rgn] = append(byRegion[rgn], r)
+	}
+
+	// An empty scan still gets a sheet with the headers
+	if len(regions) == 0 {
+		regions = []string{"Resources"}
+	}
+
+	header := columnHeaderRow(columns)
+	for i, rgn := range regions {
+		if i == 0 {
+			f.SetSheetName(f.GetSheetName(0), rgn)
+		} else {
+			f.NewSheet(rgn)
+		}
+
+		if err := f.SetSheetRow(rgn, "A1", &header); err != nil {
+			return err
+		}
+		for j, r := range byRegion[rgn] {
+			cell, err := excelize.CoordinatesToCellName(1, j+2)
+			if err != nil {
+				return err
+			}
+			row := r.row(columns)
+			if err := f.SetSheetRow(rgn, cell, &row); err != nil {
+				return err
+			}
+		}
+		if err := f.SetPanes(rgn, frozenHeader); err != nil {
+			return err
+		}
+	}
+
+	return f.Write(w)
+}
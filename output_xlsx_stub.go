@@ -0,0 +1,15 @@
+//go:build !xlsx
+// +build !xlsx
+
+package main
+
+import (
+	"errors"
+	"io"
+)
+
+// RenderXLSX is only available when built with the xlsx tag, which keeps
+// the spreadsheet library out of the default binary
+func RenderXLSX(w io.Writer, resources []*SingleResource, columns []string) error {
+	return errors.New("xlsx output isn't available in this build, rebuild with -tags xlsx")
+}
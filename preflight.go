@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Preflight checks that a scan of the regions would work without running
+// it, for --preflight: that the credentials are valid, with STS
+// GetCallerIdentity, and that the tagging API answers in every region,
+// with a GetResources call asking for a single resource. It writes a line
+// per check, failing if any of them did.
+func Preflight(ctx context.Context, w io.Writer, cfg aws.Config, regions []string) error {
+	id, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		re := NewRegionError("credentials", err)
+		fmt.Fprintf(w, "FAIL credentials: %s\n", re.Message)
+		return fmt.Errorf("--preflight: the credentials don't work, no point checking the regions")
+	}
+	fmt.Fprintf(w, "ok   credentials: %s (account %s)\n", aws.ToString(id.Arn), aws.ToString(id.Account))
+
+	failed := 0
+	for _, region := range regions {
+		r := resourcegroupstaggingapi.NewFromConfig(cfg, func(o *resourcegroupstaggingapi.Options) {
+			o.Region = region
+		})
+		_, err := r.GetResources(ctx, &resourcegroupstaggingapi.GetResourcesInput{ResourcesPerPage: aws.Int32(1)})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			re := NewRegionError(region, err)
+			failed++
+			if re.BlockedBySCP {
+				fmt.Fprintf(w, "FAIL %s: blocked by SCP (%s)\n", region, re.Message)
+			} else {
+				fmt.Fprintf(w, "FAIL %s: %s\n", region, re.Message)
+			}
+			continue
+		}
+		fmt.Fprintf(w, "ok   %s: tagging API reachable\n", region)
+	}
+	if failed > 0 {
+		return fmt.Errorf("--preflight: %d of %d regions can't be scanned", failed, len(regions))
+	}
+	return nil
+}
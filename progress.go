@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// progressWidth is the width of the bar itself, in characters
+const progressWidth = 30
+
+// Progress draws a progress bar for the scan on a terminal. The tagging API
+// doesn't say how many resources there are up front, so without an
+// expected total it only shows the running count and rate. A nil Progress
+// does nothing, which is what NewProgress returns when w isn't a terminal.
+type Progress struct {
+	w        io.Writer
+	expected int
+	start    time.Time
+	lastDraw time.Time
+	count    int
+}
+
+// NewProgress returns a Progress drawing on stderr, or nil when stderr
+// isn't a terminal
+func NewProgress(expected int) *Progress {
+	if !term.IsTerminal(int(os.Stderr.Fd())) {
+		return nil
+	}
+	return &Progress{w: os.Stderr, expected: expected, start: time.Now()}
+}
+
+// Add counts a fetched resource of region. Redraws are limited to a few a
+// second so huge scans don't spend their time drawing.
+func (p *Progress) Add(region string) {
+	if p == nil {
+		return
+	}
+	p.count++
+	if now := time.Now(); now.Sub(p.lastDraw) >= 100*time.Millisecond {
+		p.lastDraw = now
+		p.draw(region)
+	}
+}
+
+func (p *Progress) draw(region string) {
+	elapsed := time.Since(p.start)
+	rate := float64(p.count) / elapsed.Seconds()
+
+	if p.expected <= 0 {
+		fmt.Fprintf(p.w, "\r\033[K%s: %d resources (%.0f/s)", region, p.count, rate)
+		return
+	}
+
+	done := p.count
+	if done > p.expected {
+		done = p.expected
+	}
+	filled := done * progressWidth / p.expected
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", progressWidth-filled)
+
+	eta := "?"
+	if rate > 0 && done < p.expected {
+		eta = (time.Duration(float64(p.expected-done)/rate) * time.Second).Round(time.Second).String()
+	}
+	fmt.Fprintf(p.w, "\r\033[K%s [%s] %d/%d %3d%% ETA %s", region, bar, p.count, p.expected, done*100/p.expected, eta)
+}
+
+// Done clears the progress line so it doesn't mix with the output
+func (p *Progress) Done() {
+	if p == nil {
+		return
+	}
+	fmt.Fprint(p.w, "\r\033[K")
+}
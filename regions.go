@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// regionPattern matches the shape of AWS region names across partitions,
+// e.g. us-east-1, eu-central-2, us-gov-west-1 or cn-northwest-1.
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-[0-9]+$`)
+
+// ParseRegions splits a comma separated list of regions, dropping
+// any blank entries.
+func ParseRegions(list string) []string {
+	return splitList(list)
+}
+
+// ReadRegionsFile reads one region per line from path. Blank lines and
+// anything after a # are ignored.
+func ReadRegionsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var regions []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			regions = append(regions, line)
+		}
+	}
+	return regions, scanner.Err()
+}
+
+// knownRegions are the AWS regions we know about. New regions can still
+// be scanned by turning the check off with --skip-region-check.
+var knownRegions = []string{
+	"af-south-1",
+	"ap-east-1", "ap-east-2",
+	"ap-northeast-1", "ap-northeast-2", "ap-northeast-3",
+	"ap-south-1", "ap-south-2",
+	"ap-southeast-1", "ap-southeast-2", "ap-southeast-3", "ap-southeast-4",
+	"ap-southeast-5", "ap-southeast-6", "ap-southeast-7",
+	"ca-central-1", "ca-west-1",
+	"cn-north-1", "cn-northwest-1",
+	"eu-central-1", "eu-central-2",
+	"eu-north-1",
+	"eu-south-1", "eu-south-2",
+	"eu-west-1", "eu-west-2", "eu-west-3",
+	"il-central-1",
+	"me-central-1", "me-south-1",
+	"mx-central-1",
+	"sa-east-1",
+	"us-east-1", "us-east-2",
+	"us-gov-east-1", "us-gov-west-1",
+	"us-west-1", "us-west-2",
+}
+
+// ValidateRegions drops duplicates and warns about (and skips) entries
+// that obviously aren't region names, keeping the original order. Well
+// formed names that aren't a known region (most likely a typo such as
+// us-esat-1) are an error unless checkKnown is false.
+func ValidateRegions(regions []string, checkKnown bool) ([]string, error) {
+	var valid []string
+	seen := map[string]bool{}
+
+	known := map[string]bool{}
+	for _, r := range knownRegions {
+		known[r] = true
+	}
+
+	for _, r := range regions {
+		if !regionPattern.MatchString(r) {
+			fmt.Fprintf(os.Stderr, "warning: skipping malformed region %q\n", r)
+			continue
+		}
+		if checkKnown && !known[r] {
+			return nil, fmt.Errorf("unknown region %q, valid regions are: %s", r, strings.Join(knownRegions, ", "))
+		}
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		valid = append(valid, r)
+	}
+	return valid, nil
+}
+
+// regionLess returns how --region-order orders regions. "alphabetical"
+// sorts them by name, a comma separated list puts its regions first in
+// that order and any others alphabetically after them. The global
+// pseudo-region always comes last.
+func regionLess(order string) func(a, b string) bool {
+	rank := map[string]int{}
+	if order != "alphabetical" {
+		for i, r := range ParseRegions(order) {
+			rank[r] = i + 1
+		}
+	}
+	return func(a, b string) bool {
+		if (a == globalRegion) != (b == globalRegion) {
+			return b == globalRegion
+		}
+		if ra, rb := rank[a], rank[b]; ra != rb {
+			if ra == 0 || rb == 0 {
+				return rb == 0
+			}
+			return ra < rb
+		}
+		return a < b
+	}
+}
+
+// OrderRegions returns the regions in the --region-order order, so they're
+// scanned (and streamed) in it
+func OrderRegions(regions []string, order string) []string {
+	ordered := append([]string(nil), regions...)
+	less := regionLess(order)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return less(ordered[i], ordered[j])
+	})
+	return ordered
+}
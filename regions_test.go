@@ -0,0 +1,91 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// captureStderr returns what fn writes to stderr
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	fn()
+	w.Close()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestReadRegionsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "regions.txt")
+	content := "# production\nus-east-1\n\n  eu-west-1  # Dublin\n\t\n#us-west-2\nap-southeast-2#Sydney\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadRegionsFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"us-east-1", "eu-west-1", "ap-southeast-2"}
+	if !equalStrings(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := ReadRegionsFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("no error for a missing file")
+	}
+}
+
+func TestValidateRegions(t *testing.T) {
+	tests := []struct {
+		name       string
+		regions    []string
+		checkKnown bool
+		want       []string
+		warning    string
+		err        string
+	}{
+		{name: "known", regions: []string{"us-east-1", "cn-north-1", "us-gov-west-1"}, checkKnown: true, want: []string{"us-east-1", "cn-north-1", "us-gov-west-1"}},
+		{name: "duplicates", regions: []string{"eu-west-1", "us-east-1", "eu-west-1"}, checkKnown: true, want: []string{"eu-west-1", "us-east-1"}},
+		{name: "malformed", regions: []string{"us-east-1", "useast1", "US-EAST-1"}, checkKnown: true, want: []string{"us-east-1"}, warning: `warning: skipping malformed region "useast1"`},
+		{name: "unknown", regions: []string{"us-east-1", "us-esat-1"}, checkKnown: true, err: `unknown region "us-esat-1"`},
+		{name: "unknown unchecked", regions: []string{"us-esat-1"}, want: []string{"us-esat-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			var err error
+			warnings := captureStderr(t, func() {
+				got, err = ValidateRegions(tt.regions, tt.checkKnown)
+			})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalStrings(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if !strings.Contains(warnings, tt.warning) || (tt.warning == "" && warnings != "") {
+				t.Errorf("got warnings %q, want %q", warnings, tt.warning)
+			}
+		})
+	}
+}
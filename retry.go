@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// ErrRetryBudgetExhausted is returned once a scan has used up all of its
+// retries, at which point it stops with whatever it fetched so far
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted, giving up with partial results")
+
+// throttleCodes are the API error codes AWS uses to tell us to slow down
+var throttleCodes = map[string]bool{
+	"Throttling":               true,
+	"ThrottlingException":      true,
+	"ThrottledException":       true,
+	"RequestLimitExceeded":     true,
+	"TooManyRequestsException": true,
+	"RequestThrottled":         true,
+}
+
+// IsThrottle reports whether err is AWS telling us to slow down
+func IsThrottle(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && throttleCodes[apiErr.ErrorCode()]
+}
+
+// NoThrottleRetryer returns the SDK's standard retryer without its retries
+// of throttled requests. Clients used with a ThrottleBreaker need it, the
+// SDK would otherwise back off and retry throttles itself before the
+// breaker ever sees them, ignoring their Retry-After and the budget.
+func NoThrottleRetryer() aws.Retryer {
+	return retry.NewStandard(func(o *retry.StandardOptions) {
+		notThrottled := retry.IsErrorRetryableFunc(func(err error) aws.Ternary {
+			if IsThrottle(err) {
+				return aws.FalseTernary
+			}
+			return aws.UnknownTernary
+		})
+		o.Retryables = append([]retry.IsErrorRetryable{notThrottled}, o.Retryables...)
+	})
+}
+
+// RetryAfter returns how long the Retry-After header of a throttled
+// response asks us to wait, either as seconds or as an HTTP date, and
+// false when there's no usable hint
+func RetryAfter(err error) (time.Duration, bool) {
+	var respErr *smithyhttp.ResponseError
+	if !errors.As(err, &respErr) || respErr.Response == nil || respErr.Response.Response == nil {
+		return 0, false
+	}
+	header := strings.TrimSpace(respErr.Response.Header.Get("Retry-After"))
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return time.Until(at), true
+	}
+	return 0, false
+}
+
+// ThrottleBreaker retries throttled requests on top of the SDK's own
+// retries, and is shared by every request of a scan. It backs off for as
+// long as the response's Retry-After asks, or exponentially when there's
+// no hint, never for longer than the cool off period. After threshold
+// consecutive throttles it pauses the whole scan for the cool off
+// period, and once budget retries have been spent it gives up.
+type ThrottleBreaker struct {
+	budget    int
+	threshold int
+	cooloff   time.Duration
+
+	mu          sync.Mutex
+	retries     int
+	consecutive int
+	pausedUntil time.Time
+}
+
+// NewThrottleBreaker creates a ThrottleBreaker allowing budget retries in
+// total and pausing for cooloff after threshold consecutive throttles
+func NewThrottleBreaker(budget, threshold int, cooloff time.Duration) *ThrottleBreaker {
+	return &ThrottleBreaker{budget: budget, threshold: threshold, cooloff: cooloff}
+}
+
+// Do calls fn, retrying it for as long as it's throttled and the retry
+// budget allows. onRetry, when set, is told about every retry before
+// backing off. A nil ThrottleBreaker just calls fn once.
+func (b *ThrottleBreaker) Do(ctx context.Context, fn func() error, onRetry func(attempt int, err error, delay time.Duration)) error {
+	if b == nil {
+		return fn()
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := sleep(ctx, b.pause()); err != nil {
+			return err
+		}
+
+		err := fn()
+		if !IsThrottle(err) {
+			b.mu.Lock()
+			b.consecutive = 0
+			b.mu.Unlock()
+			return err
+		}
+
+		hint, _ := RetryAfter(err)
+		wait, ok := b.throttled(hint)
+		if !ok {
+			return ErrRetryBudgetExhausted
+		}
+		if onRetry != nil {
+			// Opening the breaker doesn't back off itself, the wait is
+			// the pause at the top of the loop
+			delay := wait
+			if delay == 0 {
+				delay = b.pause()
+			}
+			onRetry(attempt, err, delay)
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// pause returns how long callers have to wait while the breaker is open
+func (b *ThrottleBreaker) pause() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return time.Until(b.pausedUntil)
+}
+
+// throttled records a throttle and works out how long to back off for,
+// the Retry-After hint when there is one, opening the breaker for
+// everyone after too many in a row. It returns false once the retry
+// budget has been spent.
+func (b *ThrottleBreaker) throttled(hint time.Duration) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.retries >= b.budget {
+		return 0, false
+	}
+	b.retries++
+	b.consecutive++
+
+	if b.consecutive >= b.threshold {
+		b.consecutive = 0
+		b.pausedUntil = time.Now().Add(b.cooloff)
+		return 0, true
+	}
+
+	wait := time.Second << uint(b.consecutive-1)
+	if hint > 0 {
+		wait = hint
+	}
+	if wait > b.cooloff {
+		wait = b.cooloff
+	}
+	return wait, true
+}
+
+// sleep waits for d, returning early with the context's error if it's
+// cancelled in the meantime
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// throttledFn returns a fn for ThrottleBreaker.Do that's throttled on its
+// first times calls, counting them in calls
+func throttledFn(times int, calls *int) func() error {
+	return func() error {
+		*calls++
+		if *calls <= times {
+			return &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+		}
+		return nil
+	}
+}
+
+// retryAfterErr is a throttle whose response carries a Retry-After header,
+// left out when it's empty
+func retryAfterErr(header string) error {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	if header != "" {
+		resp.Header.Set("Retry-After", header)
+	}
+	return &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: resp},
+		Err:      &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"},
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	httpDate := func(d time.Duration) string { return time.Now().Add(d).UTC().Format(http.TimeFormat) }
+	tests := []struct {
+		name     string
+		err      error
+		ok       bool
+		min, max time.Duration
+	}{
+		{name: "seconds", err: retryAfterErr("3"), ok: true, min: 3 * time.Second, max: 3 * time.Second},
+		{name: "padded seconds", err: retryAfterErr(" 3 "), ok: true, min: 3 * time.Second, max: 3 * time.Second},
+		{name: "zero seconds", err: retryAfterErr("0"), ok: true},
+		// HTTP dates only have whole seconds
+		{name: "http date", err: retryAfterErr(httpDate(90 * time.Second)), ok: true, min: 88 * time.Second, max: 90 * time.Second},
+		{name: "http date in the past", err: retryAfterErr(httpDate(-time.Hour)), ok: true, min: -time.Hour - 2*time.Second, max: -time.Hour + time.Second},
+		{name: "negative seconds", err: retryAfterErr("-3")},
+		{name: "garbage", err: retryAfterErr("soon")},
+		{name: "no header", err: retryAfterErr("")},
+		{name: "no response", err: &smithyhttp.ResponseError{Err: errors.New("boom")}},
+		{name: "plain error", err: &smithy.GenericAPIError{Code: "ThrottlingException"}},
+	}
+	for _, tt := range tests {
+		got, ok := RetryAfter(tt.err)
+		if ok != tt.ok || got < tt.min || got > tt.max {
+			t.Errorf("%s: got %v, %v, want between %v and %v, %v", tt.name, got, ok, tt.min, tt.max, tt.ok)
+		}
+	}
+}
+
+func TestThrottleBreakerBacksOffForRetryAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		hint    time.Duration
+		cooloff time.Duration
+		want    time.Duration
+	}{
+		{name: "hint", hint: 3 * time.Second, cooloff: time.Minute, want: 3 * time.Second},
+		{name: "hint past the cool off", hint: 3 * time.Minute, cooloff: time.Minute, want: time.Minute},
+		// A date in the past is no hint at all, it backs off exponentially
+		{name: "hint in the past", hint: -time.Hour, cooloff: time.Minute, want: time.Second},
+		{name: "no hint", cooloff: time.Minute, want: time.Second},
+		{name: "no hint past the cool off", cooloff: 500 * time.Millisecond, want: 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		b := NewThrottleBreaker(10, 10, tt.cooloff)
+		wait, ok := b.throttled(tt.hint)
+		if !ok || wait != tt.want {
+			t.Errorf("%s: got %v, %v, want %v", tt.name, wait, ok, tt.want)
+		}
+	}
+}
+
+func TestThrottleBreakerDoHonoursRetryAfter(t *testing.T) {
+	// Retry-After asks for a second, far longer than the cool off, so Do
+	// waits for the cool off instead, and opens the breaker on the
+	// second throttle in a row
+	cooloff := 20 * time.Millisecond
+	b := NewThrottleBreaker(10, 2, cooloff)
+	var calls int
+	var delays []time.Duration
+	var open []bool
+	fn := func() error {
+		calls++
+		if calls <= 2 {
+			return retryAfterErr("1")
+		}
+		return nil
+	}
+	start := time.Now()
+	err := b.Do(context.Background(), fn, func(attempt int, err error, delay time.Duration) {
+		delays = append(delays, delay)
+		open = append(open, b.pause() > 0)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delays) != 2 || delays[0] != cooloff {
+		t.Fatalf("got delays %v, want the first one capped at %v", delays, cooloff)
+	}
+	if open[0] || !open[1] || delays[1] <= 0 || delays[1] > cooloff {
+		t.Errorf("got breaker open %v with delays %v, want it open after the second throttle for up to %v", open, delays, cooloff)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("Do took %v, the Retry-After wasn't capped", elapsed)
+	}
+}
+
+func TestNoThrottleRetryerLeavesThrottlesToTheBreaker(t *testing.T) {
+	r := NoThrottleRetryer()
+	for code := range throttleCodes {
+		if r.IsErrorRetryable(&smithy.GenericAPIError{Code: code}) {
+			t.Errorf("%s is retried by the SDK", code)
+		}
+	}
+	if !r.IsErrorRetryable(&smithy.GenericAPIError{Code: "RequestTimeout"}) {
+		t.Error("RequestTimeout is no longer retried by the SDK")
+	}
+}
+
+func TestThrottleBreakerRetriesThrottles(t *testing.T) {
+	b := NewThrottleBreaker(10, 10, time.Millisecond)
+	var calls, retries int
+	err := b.Do(context.Background(), throttledFn(2, &calls), func(int, error, time.Duration) { retries++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 || retries != 2 {
+		t.Errorf("got %d calls and %d retries, want 3 and 2", calls, retries)
+	}
+
+	// Other errors aren't the breaker's business
+	boom := errors.New("boom")
+	calls = 0
+	err = b.Do(context.Background(), func() error { calls++; return boom }, nil)
+	if err != boom || calls != 1 {
+		t.Errorf("got %v after %d calls, want boom after 1", err, calls)
+	}
+}
+
+func TestThrottleBreakerGivesUpOnceBudgetIsSpent(t *testing.T) {
+	b := NewThrottleBreaker(3, 10, time.Millisecond)
+	var calls int
+	err := b.Do(context.Background(), throttledFn(100, &calls), nil)
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("got %v, want ErrRetryBudgetExhausted", err)
+	}
+	if calls != 4 {
+		t.Errorf("got %d calls, want the first one and 3 retries", calls)
+	}
+
+	// The budget is shared by the whole scan, so later requests don't
+	// get to retry either
+	calls = 0
+	err = b.Do(context.Background(), throttledFn(1, &calls), nil)
+	if !errors.Is(err, ErrRetryBudgetExhausted) || calls != 1 {
+		t.Errorf("got %v after %d calls, want ErrRetryBudgetExhausted after 1", err, calls)
+	}
+}
+
+func TestThrottleBreakerPausesAfterThreshold(t *testing.T) {
+	cooloff := 20 * time.Millisecond
+	b := NewThrottleBreaker(10, 2, cooloff)
+	var calls int
+	var open []bool
+	var delays []time.Duration
+	start := time.Now()
+	err := b.Do(context.Background(), throttledFn(2, &calls), func(attempt int, err error, delay time.Duration) {
+		open = append(open, b.pause() > 0)
+		delays = append(delays, delay)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 2 || open[0] || !open[1] {
+		t.Fatalf("got breaker open %v on the retries, want only after the second throttle", open)
+	}
+	if delays[1] <= 0 || delays[1] > cooloff {
+		t.Errorf("got a delay of %v once open, want up to the %v cool off", delays[1], cooloff)
+	}
+	if elapsed := time.Since(start); elapsed < 2*cooloff-5*time.Millisecond {
+		t.Errorf("Do returned after %v, before the breaker closed again", elapsed)
+	}
+	if b.consecutive != 0 {
+		t.Errorf("got %d consecutive throttles after a success, want 0", b.consecutive)
+	}
+}
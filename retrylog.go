@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// RetryEvent is one line of the --retry-log
+type RetryEvent struct {
+	Time    time.Time `json:"time"`
+	Region  string    `json:"region"`
+	Page    int       `json:"page"`
+	Attempt int       `json:"attempt"`
+	Error   string    `json:"error"`
+	DelayMS int64     `json:"delayMs"`
+}
+
+// RetryLog appends a RetryEvent as a JSON object per line to a file for
+// every page that's retried, to look into throttled scans afterwards
+type RetryLog struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+// OpenRetryLog opens path for appending, creating it if needed
+func OpenRetryLog(path string) (*RetryLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &RetryLog{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Hook returns a ScanOptions.RetryHook writing to the log. Retries can
+// come from several regions or resource types at once, so writes are
+// serialised.
+func (l *RetryLog) Hook() func(string, int, int, error, time.Duration) {
+	return func(region string, page, attempt int, err error, delay time.Duration) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		// Losing a log line isn't worth failing the scan over
+		_ = l.enc.Encode(RetryEvent{
+			Time:    time.Now().UTC(),
+			Region:  region,
+			Page:    page,
+			Attempt: attempt,
+			Error:   err.Error(),
+			DelayMS: delay.Milliseconds(),
+		})
+	}
+}
+
+// Close closes the log file
+func (l *RetryLog) Close() error {
+	return l.f.Close()
+}
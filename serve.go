@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The messages below are the hand written Go side of proto/awslist.proto.
+// The protobuf struct tags are all the protobuf runtime needs to encode
+// them, so there's no protoc step in the build.
+
+// pbListResourcesRequest is the awslist.ListResourcesRequest message
+type pbListResourcesRequest struct {
+	Regions       []string `protobuf:"bytes,1,rep,name=regions,proto3"`
+	ResourceTypes []string `protobuf:"bytes,2,rep,name=resource_types,json=resourceTypes,proto3"`
+}
+
+func (m *pbListResourcesRequest) Reset()         { *m = pbListResourcesRequest{} }
+func (m *pbListResourcesRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*pbListResourcesRequest) ProtoMessage()    {}
+
+// pbResource is the awslist.Resource message, mirroring SingleResource
+type pbResource struct {
+	Region      string            `protobuf:"bytes,1,opt,name=region,proto3"`
+	Account     string            `protobuf:"bytes,2,opt,name=account,proto3"`
+	AccountName string            `protobuf:"bytes,3,opt,name=account_name,json=accountName,proto3"`
+	Service     string            `protobuf:"bytes,4,opt,name=service,proto3"`
+	ServiceCode string            `protobuf:"bytes,5,opt,name=service_code,json=serviceCode,proto3"`
+	Product     string            `protobuf:"bytes,6,opt,name=product,proto3"`
+	Details     string            `protobuf:"bytes,7,opt,name=details,proto3"`
+	ID          string            `protobuf:"bytes,8,opt,name=id,proto3"`
+	ARN         string            `protobuf:"bytes,9,opt,name=arn,proto3"`
+	Tags        map[string]string `protobuf:"bytes,10,rep,name=tags,proto3" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Partition   string            `protobuf:"bytes,11,opt,name=partition,proto3"`
+}
+
+func (m *pbResource) Reset()         { *m = pbResource{} }
+func (m *pbResource) String() string { return fmt.Sprintf("%+v", *m) }
+func (*pbResource) ProtoMessage()    {}
+
+func newPBResource(r *SingleResource) *pbResource {
+	return &pbResource{
+		Region:      DerefNilPointerStrings(r.Region),
+		Account:     DerefNilPointerStrings(r.Account),
+		AccountName: DerefNilPointerStrings(r.AccountName),
+		Service:     DerefNilPointerStrings(r.Service),
+		ServiceCode: DerefNilPointerStrings(r.ServiceCode),
+		Product:     DerefNilPointerStrings(r.Product),
+		Details:     DerefNilPointerStrings(r.Details),
+		ID:          DerefNilPointerStrings(r.ID),
+		ARN:         DerefNilPointerStrings(r.ARN),
+		Tags:        r.Tags,
+		Partition:   DerefNilPointerStrings(r.Partition),
+	}
+}
+
+// inventoryServer implements the awslist.Inventory service
+type inventoryServer struct {
+	cfg     aws.Config
+	regions []string
+}
+
+var inventoryServiceDesc = grpc.ServiceDesc{
+	ServiceName: "awslist.Inventory",
+	HandlerType: (*inventoryServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListResources",
+			Handler:       listResourcesHandler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/awslist.proto",
+}
+
+func listResourcesHandler(srv interface{}, stream grpc.ServerStream) error {
+	req := &pbListResourcesRequest{}
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	return srv.(*inventoryServer).ListResources(req, stream)
+}
+
+// ListResources scans the requested regions with the same fetch and
+// convert logic as the CLI, sending every resource as soon as it's parsed.
+// Regions that fail don't stop the others, they're reported in the final
+// status once everything else has been sent.
+func (s *inventoryServer) ListResources(req *pbListResourcesRequest, stream grpc.ServerStream) error {
+	ctx := stream.Context()
+
+	regions := s.regions
+	if len(req.Regions) > 0 {
+		var err error
+		if regions, err = ValidateRegions(req.Regions, !*skipRegionCheckFlag); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	// Every request gets its own retry budget, so one unlucky scan
+	// doesn't starve the ones after it
+	opts := ScanOptions{
+		ResourceTypes:    req.ResourceTypes,
+		Breaker:          NewThrottleBreaker(*retryBudgetFlag, *breakerThresholdFlag, *cooloffFlag),
+		PageDelay:        *minPageDelayFlag,
+		EmptyPageRetries: *retryOnEmptyPageFlag,
+	}
+
+	var scanErrs ScanErrors
+	// Same as the CLI, global resources are only sent once, under the
+	// global region, however many regions return them
+	seenGlobal := map[string]bool{}
+	for _, region := range regions {
+		r := resourcegroupstaggingapi.NewFromConfig(s.cfg, func(o *resourcegroupstaggingapi.Options) {
+			o.Region = region
+			o.Retryer = NoThrottleRetryer()
+		})
+		err := FetchResources(ctx, r, region, opts, func(res *SingleResource) error {
+			if IsGlobalResource(res) {
+				if seenGlobal[*res.ARN] {
+					return nil
+				}
+				seenGlobal[*res.ARN] = true
+				region := globalRegion
+				res.Region = &region
+			}
+			return stream.SendMsg(newPBResource(res))
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			scanErrs = append(scanErrs, NewRegionError(region, err))
+			if errors.Is(err, ErrRetryBudgetExhausted) {
+				break
+			}
+		}
+	}
+
+	if len(scanErrs) > 0 {
+		return status.Error(codes.Unavailable, scanErrs.Error())
+	}
+	return nil
+}
+
+// Serve runs the gRPC inventory service on addr until ctx is cancelled.
+// regions are scanned when a request doesn't ask for any in particular.
+func Serve(ctx context.Context, addr string, cfg aws.Config, regions []string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := grpc.NewServer()
+	srv.RegisterService(&inventoryServiceDesc, &inventoryServer{cfg: cfg, regions: regions})
+
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+
+	fmt.Fprintf(os.Stderr, "serving awslist.Inventory on %s\n", lis.Addr())
+	return srv.Serve(lis)
+}
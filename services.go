@@ -0,0 +1,143 @@
+package main
+
+import "strings"
+
+// friendlyServiceNames maps ARN service codes to the names people
+// actually call those services. Codes missing from here are left as is.
+var friendlyServiceNames = map[string]string{
+	"elasticloadbalancing":    "elb",
+	"cognito-idp":             "cognito",
+	"states":                  "step-functions",
+	"es":                      "opensearch",
+	"elasticfilesystem":       "efs",
+	"elasticmapreduce":        "emr",
+	"elasticbeanstalk":        "beanstalk",
+	"mobiletargeting":         "pinpoint",
+	"execute-api":             "api-gateway",
+	"apigateway":              "api-gateway",
+	"monitoring":              "cloudwatch",
+	"events":                  "eventbridge",
+	"logs":                    "cloudwatch-logs",
+	"kafka":                   "msk",
+	"catalog":                 "service-catalog",
+	"servicecatalog":          "service-catalog",
+	"wafv2":                   "waf",
+	"acm-pca":                 "private-ca",
+	"ssm":                     "systems-manager",
+	"secretsmanager":          "secrets-manager",
+	"kinesisanalytics":        "kinesis-analytics",
+	"firehose":                "kinesis-firehose",
+	"elastictranscoder":       "elastic-transcoder",
+	"application-autoscaling": "app-autoscaling",
+	"route53resolver":         "route53-resolver",
+	"globalaccelerator":       "global-accelerator",
+	"directconnect":           "direct-connect",
+	"codestar-connections":    "codestar",
+	"servicediscovery":        "cloud-map",
+	"iotanalytics":            "iot-analytics",
+	"ds":                      "directory-service",
+	"kafkaconnect":            "msk-connect",
+	"verifiedpermissions":     "verified-permissions",
+	"sms-voice":               "end-user-messaging",
+}
+
+// ApplyFriendlyServiceName swaps the resource's Service for its friendly
+// name, keeping the raw ARN service code around in ServiceCode.
+func ApplyFriendlyServiceName(r *SingleResource) {
+	if r.Service == nil {
+		return
+	}
+	name, ok := friendlyServiceNames[*r.Service]
+	if !ok || name == *r.Service {
+		return
+	}
+	code := *r.Service
+	r.ServiceCode = &code
+	r.Service = &name
+}
+
+// vpcResourceTypes are the EC2 resource types selected by the virtual
+// "vpc" service
+var vpcResourceTypes = map[string]bool{
+	"vpc":                                 true,
+	"subnet":                              true,
+	"route-table":                         true,
+	"internet-gateway":                    true,
+	"egress-only-internet-gateway":        true,
+	"nat-gateway":                         true,
+	"security-group":                      true,
+	"security-group-rule":                 true,
+	"network-acl":                         true,
+	"network-interface":                   true,
+	"elastic-ip":                          true,
+	"dhcp-options":                        true,
+	"prefix-list":                         true,
+	"vpc-endpoint":                        true,
+	"vpc-endpoint-service":                true,
+	"vpc-peering-connection":              true,
+	"vpc-flow-log":                        true,
+	"transit-gateway":                     true,
+	"transit-gateway-attachment":          true,
+	"transit-gateway-route-table":         true,
+	"customer-gateway":                    true,
+	"vpn-gateway":                         true,
+	"vpn-connection":                      true,
+	"ipam":                                true,
+	"ipam-pool":                           true,
+	"ipam-scope":                          true,
+	"ipam-resource-discovery":             true,
+	"ipam-resource-discovery-association": true,
+}
+
+// MatchesService reports whether the resource belongs to one of the given
+// ARN service codes. Besides real service codes, "vpc" matches all the
+// VPC related EC2 resources (vpcs, subnets, route tables, gateways, ...).
+func MatchesService(r *SingleResource, services []string) bool {
+	svc := DerefNilPointerStrings(r.Service)
+	for _, s := range services {
+		if s == svc {
+			return true
+		}
+		if s == "vpc" && svc == "ec2" && vpcResourceTypes[DerefNilPointerStrings(r.Product)] {
+			return true
+		}
+	}
+	return false
+}
+
+// globalServices are the services whose resources don't live in a
+// region. The tagging API returns them from every region scanned, so
+// each of them is only listed once, under the global region.
+var globalServices = map[string]bool{
+	"iam":               true,
+	"cloudfront":        true,
+	"route53":           true,
+	"waf":               true,
+	"organizations":     true,
+	"globalaccelerator": true,
+	"networkmanager":    true,
+	"health":            true,
+	"shield":            true,
+}
+
+// IsGlobalResource reports whether the resource belongs to a global
+// service, going by the ARN service code so --friendly-names doesn't
+// get in the way. Only ARNs without a region count.
+func IsGlobalResource(r *SingleResource) bool {
+	if r.ARN == nil {
+		return false
+	}
+	s := strings.SplitN(*r.ARN, ":", 5)
+	return len(s) == 5 && globalServices[s[2]] && s[3] == ""
+}
+
+// HasARNRegion reports whether the resource's ARN has a region in it.
+// Global resources don't, but neither do S3 buckets. CLOUDFRONT scope WAFv2
+// resources are global too, even if their ARNs say us-east-1.
+func HasARNRegion(r *SingleResource) bool {
+	s := strings.SplitN(DerefNilPointerStrings(r.ARN), ":", 6)
+	if len(s) == 6 && s[2] == "wafv2" && strings.HasPrefix(s[5], "global/") {
+		return false
+	}
+	return len(s) >= 5 && s[3] != ""
+}
@@ -0,0 +1,448 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"golang.org/x/term"
+)
+
+// ServiceSummary holds how many resources of a service were found,
+// in total and per region. When summarizing by tag it holds the count
+// of a tag value instead, in Tag, and by category that of a Category.
+type ServiceSummary struct {
+	Service  string         `json:"service,omitempty"`
+	Tag      string         `json:"tag,omitempty"`
+	Category string         `json:"category,omitempty"`
+	Count    int            `json:"count"`
+	Regions  map[string]int `json:"regions"`
+}
+
+// name is what the summary is about, its service, tag value or category
+func (s *ServiceSummary) name() string {
+	switch {
+	case s.Tag != "":
+		return s.Tag
+	case s.Category != "":
+		return s.Category
+	}
+	return s.Service
+}
+
+// untaggedValue is the bucket SummarizeByTag counts resources without
+// the tag in
+const untaggedValue = "(none)"
+
+// SummarizeResources counts the resources per service, dropping the
+// services with fewer than minResources resources. The busiest services
+// come first. Resources of global services are counted once, under the
+// "global" region, however many regions returned them.
+func SummarizeResources(resources []*SingleResource, minResources int) []*ServiceSummary {
+	return summarize(resources, minResources, func(r *SingleResource) *ServiceSummary {
+		return &ServiceSummary{Service: DerefNilPointerStrings(r.Service)}
+	})
+}
+
+// SummarizeByTag is like SummarizeResources but counts the resources per
+// value of the given tag, those without it under "(none)"
+func SummarizeByTag(resources []*SingleResource, key string, minResources int) []*ServiceSummary {
+	return summarize(resources, minResources, func(r *SingleResource) *ServiceSummary {
+		if v, ok := r.Tags[key]; ok && v != "" {
+			return &ServiceSummary{Tag: v}
+		}
+		return &ServiceSummary{Tag: untaggedValue}
+	})
+}
+
+// SummarizeByCategory is like SummarizeResources but counts the resources
+// per category of their service (Compute, Storage, ...), for high level
+// reports
+func SummarizeByCategory(resources []*SingleResource, categories CategoryMap, minResources int) []*ServiceSummary {
+	return summarize(resources, minResources, func(r *SingleResource) *ServiceSummary {
+		return &ServiceSummary{Category: categories.Category(r)}
+	})
+}
+
+// summarize counts the resources per the summary group returns for them
+func summarize(resources []*SingleResource, minResources int, group func(*SingleResource) *ServiceSummary) []*ServiceSummary {
+	byName := map[string]*ServiceSummary{}
+	seenGlobal := map[string]bool{}
+
+	for _, r := range resources {
+		region := DerefNilPointerStrings(r.Region)
+		if IsGlobalResource(r) {
+			if seenGlobal[*r.ARN] {
+				continue
+			}
+			seenGlobal[*r.ARN] = true
+			region = globalRegion
+		}
+
+		g := group(r)
+		s, ok := byName[g.name()]
+		if !ok {
+			s = g
+			s.Regions = map[string]int{}
+			byName[g.name()] = s
+		}
+		s.Count++
+		s.Regions[region]++
+	}
+
+	var summaries []*ServiceSummary
+	for _, s := range byName {
+		if s.Count >= minResources {
+			summaries = append(summaries, s)
+		}
+	}
+
+	SortSummaries(summaries, false)
+	return summaries
+}
+
+// SortSummaries orders the summaries by resource count, ascending or
+// descending. Ties are always broken alphabetically by service name so
+// the output is stable.
+func SortSummaries(summaries []*ServiceSummary, ascending bool) {
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Count != summaries[j].Count {
+			if ascending {
+				return summaries[i].Count < summaries[j].Count
+			}
+			return summaries[i].Count > summaries[j].Count
+		}
+		return summaries[i].name() < summaries[j].name()
+	})
+}
+
+// jsonSummary is the top level object written for --summary --output json
+type jsonSummary struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	Services      []*ServiceSummary `json:"services"`
+	Errors        ScanErrors        `json:"errors,omitempty"`
+}
+
+// RenderSummary writes the summaries in the given output format. The
+// json output also lists the regions that couldn't be scanned.
+func RenderSummary(w io.Writer, output string, summaries []*ServiceSummary, errs ScanErrors, pretty bool) error {
+	switch output {
+	case "table":
+		table := tablewriter.NewWriter(w)
+		header := "Service"
+		if len(summaries) > 0 && summaries[0].Tag != "" {
+			header = "Tag value"
+		} else if len(summaries) > 0 && summaries[0].Category != "" {
+			header = "Category"
+		}
+		table.SetHeader([]string{header, "Count", "Regions"})
+		table.SetBorder(true)
+		for _, s := range summaries {
+			table.Append([]string{s.name(), strconv.Itoa(s.Count), regionCounts(s.Regions)})
+		}
+		table.Render()
+		return nil
+	case "json":
+		if summaries == nil {
+			summaries = []*ServiceSummary{}
+		}
+		return jsonEncoder(w, pretty).Encode(jsonSummary{SchemaVersion: OutputSchemaVersion, Services: summaries, Errors: errs})
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		for _, s := range summaries {
+			if err := enc.Encode(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("output format %q isn't supported with --summary", output)
+}
+
+// TagCoverage is how many of the resources of a service have all the tags
+// of --tag-coverage, for tracking cost allocation tagging
+type TagCoverage struct {
+	Service string `json:"service"`
+	Tagged  int    `json:"tagged"`
+	Count   int    `json:"count"`
+	Percent int    `json:"percent"`
+}
+
+// SummarizeTagCoverage works out the tag coverage of every service,
+// counting a resource as tagged when it has all the keys set to a non
+// blank value, like --fail-on-untagged does. Services are dropped by
+// minResources and ordered by resource count like with SummarizeResources.
+func SummarizeTagCoverage(resources []*SingleResource, keys []string, minResources int, ascending bool) []*TagCoverage {
+	var tagged []*SingleResource
+	for _, r := range resources {
+		if len(r.Tags.Missing(keys)) == 0 {
+			tagged = append(tagged, r)
+		}
+	}
+	taggedCounts := map[string]int{}
+	for _, s := range SummarizeResources(tagged, 0) {
+		taggedCounts[s.Service] = s.Count
+	}
+
+	summaries := SummarizeResources(resources, minResources)
+	if ascending {
+		SortSummaries(summaries, true)
+	}
+	var coverage []*TagCoverage
+	for _, s := range summaries {
+		coverage = append(coverage, &TagCoverage{
+			Service: s.Service,
+			Tagged:  taggedCounts[s.Service],
+			Count:   s.Count,
+			Percent: taggedCounts[s.Service] * 100 / s.Count,
+		})
+	}
+	return coverage
+}
+
+// jsonTagCoverage is the top level object written for --tag-coverage
+// --output json
+type jsonTagCoverage struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	TagKeys       []string       `json:"tagKeys"`
+	Services      []*TagCoverage `json:"services"`
+	Errors        ScanErrors     `json:"errors,omitempty"`
+}
+
+// RenderTagCoverage writes the tag coverage in the given output format.
+// The table output is a line per service, e.g. "ec2: 86% (356/412)
+// tagged", followed by the total across all of them. Percentages are
+// rounded down so 100% means every resource is tagged.
+func RenderTagCoverage(w io.Writer, output string, coverage []*TagCoverage, keys []string, errs ScanErrors, pretty bool) error {
+	switch output {
+	case "table":
+		tagged, count := 0, 0
+		for _, c := range coverage {
+			if _, err := fmt.Fprintf(w, "%s: %d%% (%d/%d) tagged\n", c.Service, c.Percent, c.Tagged, c.Count); err != nil {
+				return err
+			}
+			tagged += c.Tagged
+			count += c.Count
+		}
+		if count == 0 {
+			return nil
+		}
+		_, err := fmt.Fprintf(w, "total: %d%% (%d/%d) tagged with %s\n", tagged*100/count, tagged, count, strings.Join(keys, ", "))
+		return err
+	case "json":
+		if coverage == nil {
+			coverage = []*TagCoverage{}
+		}
+		return jsonEncoder(w, pretty).Encode(jsonTagCoverage{SchemaVersion: OutputSchemaVersion, TagKeys: keys, Services: coverage, Errors: errs})
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		for _, c := range coverage {
+			if err := enc.Encode(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("output format %q isn't supported with --tag-coverage", output)
+}
+
+// influxEscaper escapes the characters that are special in the tag keys
+// and values of the InfluxDB line protocol
+var influxEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// RenderInflux writes the summaries as InfluxDB line protocol points, one
+// aws_resources point per service (or value of the tagKey tag, or category)
+// and region, all timestamped at. It's meant to be fed to Telegraf or influx
+// write to chart resource counts over time.
+func RenderInflux(w io.Writer, summaries []*ServiceSummary, tagKey string, at time.Time) error {
+	for _, s := range summaries {
+		group := "service=" + influxEscaper.Replace(s.Service)
+		if s.Tag != "" {
+			group = influxEscaper.Replace(tagKey) + "=" + influxEscaper.Replace(s.Tag)
+		} else if s.Category != "" {
+			group = "category=" + influxEscaper.Replace(s.Category)
+		}
+
+		regions := make([]string, 0, len(s.Regions))
+		for r := range s.Regions {
+			regions = append(regions, r)
+		}
+		sort.Strings(regions)
+
+		for _, r := range regions {
+			_, err := fmt.Fprintf(w, "aws_resources,%s,region=%s count=%di %d\n", group, influxEscaper.Replace(r), s.Regions[r], at.UnixNano())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// regionCounts formats per region counts as "eu-west-1 (2), us-east-1 (3)"
+func regionCounts(regions map[string]int) string {
+	names := make([]string, 0, len(regions))
+	for r := range regions {
+		names = append(names, r)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, r := range names {
+		parts[i] = fmt.Sprintf("%s (%d)", r, regions[r])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RenderSummaryChart draws the summaries as a horizontal bar chart, each
+// service's bar made of # characters in proportion to its count and
+// scaled so the biggest one fills the given width.
+func RenderSummaryChart(w io.Writer, summaries []*ServiceSummary, width int) error {
+	nameWidth, countWidth, max := 0, 0, 0
+	for _, s := range summaries {
+		if len(s.name()) > nameWidth {
+			nameWidth = len(s.name())
+		}
+		if c := len(strconv.Itoa(s.Count)); c > countWidth {
+			countWidth = c
+		}
+		if s.Count > max {
+			max = s.Count
+		}
+	}
+
+	barWidth := width - nameWidth - countWidth - 2
+	if barWidth < 10 {
+		barWidth = 10
+	}
+
+	for _, s := range summaries {
+		bar := s.Count * barWidth / max
+		if bar == 0 && s.Count > 0 {
+			bar = 1
+		}
+		if _, err := fmt.Fprintf(w, "%-*s %*d %s\n", nameWidth, s.name(), countWidth, s.Count, strings.Repeat("#", bar)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// terminalWidth returns the width of the terminal stdout is attached to,
+// falling back to $COLUMNS and then 80 columns
+func terminalWidth() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return 80
+}
+
+// ResourceTypeCount is how many resources of a tagging API resource type
+// (service:type, e.g. ec2:instance) were found
+type ResourceTypeCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// CountResourceTypes counts the resources per resource type, most common
+// first. The types are built from the raw ARN service code so they can be
+// passed straight to --resource-type.
+func CountResourceTypes(resources []*SingleResource) []*ResourceTypeCount {
+	counts := map[string]int{}
+	for _, r := range resources {
+		svc := DerefNilPointerStrings(r.Service)
+		if r.ServiceCode != nil {
+			svc = *r.ServiceCode
+		}
+		t := svc
+		if product := DerefNilPointerStrings(r.Product); product != "" {
+			t += ":" + product
+		}
+		counts[t]++
+	}
+
+	types := make([]*ResourceTypeCount, 0, len(counts))
+	for t, c := range counts {
+		types = append(types, &ResourceTypeCount{Type: t, Count: c})
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].Count != types[j].Count {
+			return types[i].Count > types[j].Count
+		}
+		return types[i].Type < types[j].Type
+	})
+	return types
+}
+
+// RenderResourceTypes writes the resource type counts in the given output
+// format
+func RenderResourceTypes(w io.Writer, output string, types []*ResourceTypeCount, pretty bool) error {
+	switch output {
+	case "table":
+		table := tablewriter.NewWriter(w)
+		table.SetHeader([]string{"Resource type", "Count"})
+		table.SetBorder(true)
+		for _, t := range types {
+			table.Append([]string{t.Type, strconv.Itoa(t.Count)})
+		}
+		table.Render()
+		return nil
+	case "json":
+		return jsonEncoder(w, pretty).Encode(types)
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		for _, t := range types {
+			if err := enc.Encode(t); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("output format %q isn't supported by the types command", output)
+}
+
+// RenderDOT writes the summaries as a Graphviz graph, with an edge from
+// every region to each service found there labelled with the count. Render
+// it with e.g. dot -Tpng.
+func RenderDOT(w io.Writer, summaries []*ServiceSummary) error {
+	var regions []string
+	seen := map[string]bool{}
+	for _, s := range summaries {
+		for r := range s.Regions {
+			if !seen[r] {
+				seen[r] = true
+				regions = append(regions, r)
+			}
+		}
+	}
+	sort.Strings(regions)
+
+	var b strings.Builder
+	b.WriteString("digraph awslist {\n\trankdir=LR;\n")
+	for _, r := range regions {
+		fmt.Fprintf(&b, "\t%q [shape=box];\n", r)
+	}
+	for _, s := range summaries {
+		fmt.Fprintf(&b, "\t%q [shape=ellipse, label=%q];\n", "service:"+s.name(), fmt.Sprintf("%s (%d)", s.name(), s.Count))
+	}
+	for _, r := range regions {
+		for _, s := range summaries {
+			if n, ok := s.Regions[r]; ok {
+				fmt.Fprintf(&b, "\t%q -> %q [label=\"%d\"];\n", r, "service:"+s.name(), n)
+			}
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
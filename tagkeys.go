@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/olekukonko/tablewriter"
+)
+
+// ListTagKeys pages through the GetTagKeys API, returning every tag key
+// used in the client's region
+func ListTagKeys(ctx context.Context, r *resourcegroupstaggingapi.Client, breaker *ThrottleBreaker) ([]string, error) {
+	var keys []string
+	var paginationToken string
+	for {
+		in := &resourcegroupstaggingapi.GetTagKeysInput{}
+		if paginationToken != "" {
+			in.PaginationToken = &paginationToken
+		}
+
+		var out *resourcegroupstaggingapi.GetTagKeysOutput
+		err := breaker.Do(ctx, func() (err error) {
+			out, err = r.GetTagKeys(ctx, in)
+			return err
+		}, nil)
+		if err != nil {
+			return keys, err
+		}
+		keys = append(keys, out.TagKeys...)
+
+		paginationToken = aws.ToString(out.PaginationToken)
+		if paginationToken == "" {
+			return keys, nil
+		}
+	}
+}
+
+// ListTagValues pages through the GetTagValues API, returning every value
+// of the tag key used in the client's region
+func ListTagValues(ctx context.Context, r *resourcegroupstaggingapi.Client, key string, breaker *ThrottleBreaker) ([]string, error) {
+	var values []string
+	var paginationToken string
+	for {
+		in := &resourcegroupstaggingapi.GetTagValuesInput{Key: &key}
+		if paginationToken != "" {
+			in.PaginationToken = &paginationToken
+		}
+
+		var out *resourcegroupstaggingapi.GetTagValuesOutput
+		err := breaker.Do(ctx, func() (err error) {
+			out, err = r.GetTagValues(ctx, in)
+			return err
+		}, nil)
+		if err != nil {
+			return values, err
+		}
+		values = append(values, out.TagValues...)
+
+		paginationToken = aws.ToString(out.PaginationToken)
+		if paginationToken == "" {
+			return values, nil
+		}
+	}
+}
+
+// forEachTagRegion calls fn with a tagging API client for every region,
+// carrying on past the regions that fail like a scan does
+func forEachTagRegion(ctx context.Context, cfg aws.Config, regions []string, fn func(r *resourcegroupstaggingapi.Client, region string) error) (ScanErrors, error) {
+	var scanErrs ScanErrors
+	for _, region := range regions {
+		r := resourcegroupstaggingapi.NewFromConfig(cfg, func(o *resourcegroupstaggingapi.Options) {
+			o.Region = region
+			o.Retryer = NoThrottleRetryer()
+		})
+		if err := fn(r, region); err != nil {
+			if ctx.Err() != nil {
+				return scanErrs, ctx.Err()
+			}
+			scanErrs = append(scanErrs, NewRegionError(region, err))
+			if errors.Is(err, ErrRetryBudgetExhausted) {
+				break
+			}
+		}
+	}
+	return scanErrs, nil
+}
+
+// listTagKeys runs the tag-keys command, printing the tag keys used in
+// any of the regions once each, sorted
+func listTagKeys(ctx context.Context, cfg aws.Config, regions []string) error {
+	breaker := NewThrottleBreaker(*retryBudgetFlag, *breakerThresholdFlag, *cooloffFlag)
+	seen := map[string]bool{}
+
+	scanErrs, err := forEachTagRegion(ctx, cfg, regions, func(r *resourcegroupstaggingapi.Client, region string) error {
+		keys, err := ListTagKeys(ctx, r, breaker)
+		for _, k := range keys {
+			seen[k] = true
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if err := RenderTagKeys(os.Stdout, *outputFlag, keys, *jsonPrettyFlag); err != nil {
+		return err
+	}
+	if len(scanErrs) > 0 {
+		return scanErrs
+	}
+	return nil
+}
+
+// RenderTagKeys writes the tag keys in the given output format
+func RenderTagKeys(w io.Writer, output string, keys []string, pretty bool) error {
+	switch output {
+	case "table":
+		table := tablewriter.NewWriter(w)
+		table.SetHeader([]string{"Tag key"})
+		table.SetBorder(true)
+		for _, k := range keys {
+			table.Append([]string{k})
+		}
+		table.Render()
+		return nil
+	case "line":
+		for _, k := range keys {
+			if _, err := fmt.Fprintln(w, k); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"Tag key"})
+		for _, k := range keys {
+			cw.Write([]string{k})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		return jsonEncoder(w, pretty).Encode(keys)
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		for _, k := range keys {
+			if err := enc.Encode(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("output format %q isn't supported by the tag-keys command", output)
+}
+
+// TagValueCount is how many resources have a tag set to Value
+type TagValueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// listTagValues runs the tag-values command for the tag key. The values
+// come from GetTagValues and the counts from a scan of the resources with
+// that tag key, values GetTagValues knows about but no listed resource has
+// get a count of 0.
+func listTagValues(ctx context.Context, cfg aws.Config, regions []string, key string) error {
+	breaker := NewThrottleBreaker(*retryBudgetFlag, *breakerThresholdFlag, *cooloffFlag)
+	counts := map[string]int{}
+	opts := ScanOptions{TagKeys: []string{key}, Breaker: breaker, PageDelay: *minPageDelayFlag}
+	count := countTagValues(key, counts)
+
+	scanErrs, err := forEachTagRegion(ctx, cfg, regions, func(r *resourcegroupstaggingapi.Client, region string) error {
+		values, err := ListTagValues(ctx, r, key, breaker)
+		for _, v := range values {
+			counts[v] += 0
+		}
+		if err != nil {
+			return err
+		}
+		return FetchResources(ctx, r, region, opts, count)
+	})
+	if err != nil {
+		return err
+	}
+
+	values := make([]*TagValueCount, 0, len(counts))
+	for v, n := range counts {
+		values = append(values, &TagValueCount{Value: v, Count: n})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+
+	if err := RenderTagValues(os.Stdout, *outputFlag, values, *jsonPrettyFlag); err != nil {
+		return err
+	}
+	if len(scanErrs) > 0 {
+		return scanErrs
+	}
+	return nil
+}
+
+// countTagValues returns a FetchResources callback adding up how many
+// resources have each value of the tag into counts. Global resources come
+// back from every region, they're only counted the first time.
+func countTagValues(key string, counts map[string]int) func(*SingleResource) error {
+	seenGlobal := map[string]bool{}
+	return func(res *SingleResource) error {
+		if IsGlobalResource(res) {
+			if seenGlobal[*res.ARN] {
+				return nil
+			}
+			seenGlobal[*res.ARN] = true
+		}
+		if v, ok := res.Tags[key]; ok {
+			counts[v]++
+		}
+		return nil
+	}
+}
+
+// RenderTagValues writes the tag values and their counts in the given
+// output format
+func RenderTagValues(w io.Writer, output string, values []*TagValueCount, pretty bool) error {
+	switch output {
+	case "table":
+		table := tablewriter.NewWriter(w)
+		table.SetHeader([]string{"Tag value", "Count"})
+		table.SetBorder(true)
+		for _, v := range values {
+			table.Append([]string{v.Value, strconv.Itoa(v.Count)})
+		}
+		table.Render()
+		return nil
+	case "line":
+		for _, v := range values {
+			if _, err := fmt.Fprintf(w, "%s %d\n", v.Value, v.Count); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"Tag value", "Count"})
+		for _, v := range values {
+			cw.Write([]string{v.Value, strconv.Itoa(v.Count)})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		return jsonEncoder(w, pretty).Encode(values)
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		for _, v := range values {
+			if err := enc.Encode(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("output format %q isn't supported by the tag-values command", output)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+)
+
+// taggedPage is a single page of resources with their Environment tag
+func taggedPage(envs map[string]string) *resourcegroupstaggingapi.GetResourcesOutput {
+	out := &resourcegroupstaggingapi.GetResourcesOutput{}
+	for arn, env := range envs {
+		out.ResourceTagMappingList = append(out.ResourceTagMappingList, types.ResourceTagMapping{
+			ResourceARN: aws.String(arn),
+			Tags:        []types.Tag{{Key: aws.String("Environment"), Value: aws.String(env)}},
+		})
+	}
+	return out
+}
+
+func TestCountTagValuesCountsGlobalResourcesOnce(t *testing.T) {
+	// IAM roles and CloudFront distributions come back from every region
+	global := map[string]string{
+		"arn:aws:iam::123456789012:role/deploy":                  "prod",
+		"arn:aws:cloudfront::123456789012:distribution/E1A2B3C4": "prod",
+	}
+	regions := map[string]map[string]string{
+		"us-east-1": {"arn:aws:ec2:us-east-1:123456789012:instance/i-0a": "prod", "arn:aws:sqs:us-east-1:123456789012:jobs": "dev"},
+		"eu-west-1": {"arn:aws:ec2:eu-west-1:123456789012:instance/i-0b": "dev"},
+	}
+
+	counts := map[string]int{}
+	count := countTagValues("Environment", counts)
+	for region, envs := range regions {
+		for arn, env := range global {
+			envs[arn] = env
+		}
+		api := &fakeTaggingAPI{pages: map[string]*resourcegroupstaggingapi.GetResourcesOutput{"": taggedPage(envs)}}
+		if err := FetchResources(context.Background(), api, region, ScanOptions{}, count); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]int{"prod": 3, "dev": 2}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("got %v, want %v", counts, want)
+	}
+}
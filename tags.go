@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+)
+
+// Tags holds the tags applied to a resource, keyed by tag key
+type Tags map[string]string
+
+// TagsFromMapping converts the tag list the tagging API returns for a
+// resource into our Tags map. Resources without tags get a nil map.
+func TagsFromMapping(tags []types.Tag) Tags {
+	if len(tags) == 0 {
+		return nil
+	}
+	t := make(Tags, len(tags))
+	for _, tag := range tags {
+		t[DerefNilPointerStrings(tag.Key)] = DerefNilPointerStrings(tag.Value)
+	}
+	return t
+}
+
+// HasAll reports whether every one of the given keys is set,
+// regardless of its value
+func (t Tags) HasAll(keys []string) bool {
+	for _, k := range keys {
+		if _, ok := t[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Missing returns the given keys that aren't set, or are set to a blank
+// value, in the order given
+func (t Tags) Missing(keys []string) []string {
+	var missing []string
+	for _, k := range keys {
+		if strings.TrimSpace(t[k]) == "" {
+			missing = append(missing, k)
+		}
+	}
+	return missing
+}
+
+// TagFilter is one of the --tag filters, a tag key and the value it must
+// have. A filter given without "=" matches any value.
+type TagFilter struct {
+	Key      string
+	Value    string
+	AnyValue bool
+}
+
+// ParseTagFilters parses comma separated key=value filters, e.g.
+// Environment=prod,Team=payments
+func ParseTagFilters(list string) []TagFilter {
+	var filters []TagFilter
+	for _, item := range splitList(list) {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) == 1 {
+			filters = append(filters, TagFilter{Key: kv[0], AnyValue: true})
+			continue
+		}
+		filters = append(filters, TagFilter{Key: kv[0], Value: kv[1]})
+	}
+	return filters
+}
+
+// Matches reports whether the tags match all of the filters, or at least
+// one of them when any is set
+func (t Tags) Matches(filters []TagFilter, any bool) bool {
+	for _, f := range filters {
+		v, ok := t[f.Key]
+		matched := ok && (f.AnyValue || v == f.Value)
+		if matched == any {
+			return matched
+		}
+	}
+	return !any
+}
+
+// Keys returns the tag keys in sorted order
+func (t Tags) Keys() []string {
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MarshalXML renders the tags as <tag key="...">value</tag> elements,
+// as encoding/xml can't handle maps on its own
+func (t Tags) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, k := range t.Keys() {
+		tag := xml.StartElement{
+			Name: xml.Name{Local: "tag"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: k}},
+		}
+		if err := e.EncodeElement(t[k], tag); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// splitList splits a comma separated flag value, dropping blank entries
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// TagViolation is a resource --fail-on-untagged found without some of the
+// required tags
+type TagViolation struct {
+	ARN     string
+	Missing []string
+}
+
+// RenderTagViolations lists the resources missing required tags, sorted
+// by ARN, each with the tags it lacks
+func RenderTagViolations(w io.Writer, violations []TagViolation) {
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].ARN < violations[j].ARN
+	})
+	fmt.Fprintf(w, "%d resources are missing required tags:\n", len(violations))
+	for _, v := range violations {
+		fmt.Fprintf(w, "  %s: missing %s\n", v.ARN, strings.Join(v.Missing, ", "))
+	}
+}
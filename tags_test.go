@@ -0,0 +1,57 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTagFilters(t *testing.T) {
+	got := ParseTagFilters("Environment=prod, Team ,Note=a=b,,Empty=")
+	want := []TagFilter{
+		{Key: "Environment", Value: "prod"},
+		{Key: "Team", AnyValue: true},
+		{Key: "Note", Value: "a=b"},
+		{Key: "Empty", Value: ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestTagsMatches(t *testing.T) {
+	tags := Tags{"Environment": "prod", "Team": "payments", "Empty": ""}
+	tests := []struct {
+		filters string
+		all     bool
+		any     bool
+	}{
+		{filters: "Environment=prod", all: true, any: true},
+		{filters: "Environment=dev", all: false, any: false},
+		{filters: "Environment=prod,Team=payments", all: true, any: true},
+		{filters: "Environment=prod,Team=search", all: false, any: true},
+		{filters: "Environment=dev,Team=search", all: false, any: false},
+		// A bare key matches any value, but the tag has to be there
+		{filters: "Team", all: true, any: true},
+		{filters: "Owner", all: false, any: false},
+		{filters: "Environment=prod,Owner", all: false, any: true},
+		// Values are compared as they are
+		{filters: "Environment=Prod", all: false, any: false},
+		{filters: "Empty=", all: true, any: true},
+		{filters: "Owner=", all: false, any: false},
+	}
+	for _, tt := range tests {
+		filters := ParseTagFilters(tt.filters)
+		if got := tags.Matches(filters, false); got != tt.all {
+			t.Errorf("%s in all mode: got %v, want %v", tt.filters, got, tt.all)
+		}
+		if got := tags.Matches(filters, true); got != tt.any {
+			t.Errorf("%s in any mode: got %v, want %v", tt.filters, got, tt.any)
+		}
+	}
+
+	// Untagged resources match nothing
+	var untagged Tags
+	if untagged.Matches(ParseTagFilters("Team"), false) || untagged.Matches(ParseTagFilters("Team,Owner"), true) {
+		t.Error("an untagged resource matched a tag filter")
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// templateResource is what --template and --template-file templates are
+// executed against, the SingleResource fields as plain strings so the
+// templates don't have to deal with nil pointers, e.g.
+// {{.Region}} {{.ID}} {{index .Tags "Owner"}}
+type templateResource struct {
+	Partition   string
+	Region      string
+	Account     string
+	AccountName string
+	Service     string
+	ServiceCode string
+	Product     string
+	Details     string
+	ID          string
+	ARN         string
+	Tags        Tags
+	MonthlyCost string
+}
+
+// LoadTemplate parses the inline template, or the one in file if given.
+// It returns nil when neither is set. The file's trailing newline is
+// dropped, StreamTemplate ends every resource with one anyway.
+func LoadTemplate(inline, file string) (*template.Template, error) {
+	if inline != "" && file != "" {
+		return nil, fmt.Errorf("--template and --template-file can't be used together")
+	}
+	if file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		inline = strings.TrimSuffix(string(b), "\n")
+	}
+	if inline == "" {
+		return nil, nil
+	}
+	return template.New("resource").Option("missingkey=zero").Parse(inline)
+}
+
+// StreamTemplate returns a callback executing the template once for every
+// resource it receives, each followed by a newline
+func StreamTemplate(w io.Writer, tmpl *template.Template) func(*SingleResource) error {
+	return func(r *SingleResource) error {
+		err := tmpl.Execute(w, templateResource{
+			Partition:   DerefNilPointerStrings(r.Partition),
+			Region:      DerefNilPointerStrings(r.Region),
+			Account:     DerefNilPointerStrings(r.Account),
+			AccountName: DerefNilPointerStrings(r.AccountName),
+			Service:     DerefNilPointerStrings(r.Service),
+			ServiceCode: DerefNilPointerStrings(r.ServiceCode),
+			Product:     DerefNilPointerStrings(r.Product),
+			Details:     DerefNilPointerStrings(r.Details),
+			ID:          DerefNilPointerStrings(r.ID),
+			ARN:         DerefNilPointerStrings(r.ARN),
+			Tags:        r.Tags,
+			MonthlyCost: DerefNilPointerStrings(r.MonthlyCost),
+		})
+		if err == nil {
+			_, err = fmt.Fprintln(w)
+		}
+		return err
+	}
+}
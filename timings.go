@@ -0,0 +1,29 @@
+package main
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// RegionTiming is how long scanning a region took and how much it returned
+type RegionTiming struct {
+	Region    string
+	Pages     int
+	Resources int
+	Duration  time.Duration
+}
+
+// RenderTimings writes the per region timings as a table, to spot the
+// regions slowing an all regions scan down
+func RenderTimings(w io.Writer, timings []RegionTiming) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Region", "Pages", "Resources", "Duration"})
+	table.SetBorder(true)
+	for _, t := range timings {
+		table.Append([]string{t.Region, strconv.Itoa(t.Pages), strconv.Itoa(t.Resources), t.Duration.Round(time.Millisecond).String()})
+	}
+	table.Render()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// clearScreen moves the cursor home and wipes the terminal
+const clearScreen = "\033[H\033[2J"
+
+// maxWatchBackoff caps how many intervals we'll wait between scans
+// while they keep failing
+const maxWatchBackoff = 8
+
+// Watch runs scan straight away and then again every interval until ctx
+// is cancelled, clearing the screen before each run. A failed scan, most
+// likely because we're being throttled, doubles the wait (up to
+// maxWatchBackoff intervals) so large accounts don't get hammered.
+func Watch(ctx context.Context, interval time.Duration, scan func(context.Context) error) {
+	delay := interval
+
+	for {
+		fmt.Print(clearScreen)
+		fmt.Printf("Every %s: awslist    %s\n\n", interval, time.Now().Format(time.RFC1123))
+
+		if err := scan(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			fmt.Fprintln(os.Stderr, err)
+			if delay < maxWatchBackoff*interval {
+				delay *= 2
+			}
+		} else {
+			delay = interval
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}